@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// z95 is the number of standard deviations on either side of the mean that cover 95% of a normal
+// distribution.
+const z95 = 1.959964
+
+// record is the outcome of the games of a player against an opponent.
+type record struct {
+	wins, draws, losses int
+
+	// disks is the sum of the disk differentials of the games, from the player's perspective.
+	disks int
+}
+
+// add returns the record with another game added, which the winner won with the given disk
+// differential. The winner is 1 for the player, 2 for the opponent, or 0 for a draw.
+func (r record) add(winner common.Disk, disks int) record {
+	switch winner {
+	case 1:
+		r.wins++
+	case 2:
+		r.losses++
+	default:
+		r.draws++
+	}
+	r.disks += disks
+	return r
+}
+
+func (r record) games() int {
+	return r.wins + r.draws + r.losses
+}
+
+func (r record) averageDisks() float64 {
+	if r.games() == 0 {
+		return 0
+	}
+	return float64(r.disks) / float64(r.games())
+}
+
+// score returns the fraction of the points that the player won, where a draw is worth half a
+// win.
+func (r record) score() float64 {
+	return (float64(r.wins) + float64(r.draws)/2) / float64(r.games())
+}
+
+// elo returns the Elo difference between the player and the opponent that the record implies,
+// and the bounds of its 95% confidence interval. The interval is the Wilson score interval of the
+// fraction of the points that the player won, which is still meaningful when the player won or lost
+// every game, unlike the Elo difference itself, which is then infinite.
+func (r record) elo() (diff, low, high float64) {
+	n := float64(r.games())
+	if n == 0 {
+		return 0, math.Inf(-1), math.Inf(1)
+	}
+
+	score := r.score()
+
+	z2 := z95 * z95
+	center := (score + z2/(2*n)) / (1 + z2/n)
+	margin := z95 / (1 + z2/n) * math.Sqrt(score*(1-score)/n+z2/(4*n*n))
+
+	low, high = center-margin, center+margin
+
+	// Rounding would otherwise keep the bounds from reaching 0 and 1 when they should.
+	if r.wins+r.draws == 0 {
+		low = 0
+	}
+	if r.losses+r.draws == 0 {
+		high = 1
+	}
+
+	return eloDifference(score), eloDifference(low), eloDifference(high)
+}
+
+// eloDifference returns the difference in Elo ratings at which a player is expected to win the
+// given fraction of the points.
+func eloDifference(score float64) float64 {
+	return -400 * math.Log10(1/score-1)
+}
+
+func formatElo(elo float64) string {
+	switch {
+	case math.IsInf(elo, 1):
+		return "+inf"
+	case math.IsInf(elo, -1):
+		return "-inf"
+	case elo == 0:
+		// Avoid printing negative zero.
+		return "+0"
+	default:
+		return fmt.Sprintf("%+.0f", elo)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// closeTo returns true if got is within a hundredth of want, or both are the same infinity.
+func closeTo(got, want float64) bool {
+	if math.IsInf(want, 0) {
+		return got == want
+	}
+	return math.Abs(got-want) < 0.01
+}
+
+func TestEloDifference(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  float64
+	}{
+		{score: 0.5, want: 0},
+		{score: 0.75, want: 190.85},
+		{score: 0.25, want: -190.85},
+		{score: 0.9, want: 381.70},
+		{score: 0.64, want: 99.95},
+		{score: 1, want: math.Inf(1)},
+		{score: 0, want: math.Inf(-1)},
+	}
+	for _, tt := range tests {
+		if got := eloDifference(tt.score); !closeTo(got, tt.want) {
+			t.Errorf("eloDifference(%v) got %v, want %v", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestRecordElo(t *testing.T) {
+	tests := []struct {
+		wins, draws, losses int
+		diff, low, high     float64
+	}{
+		{diff: 0, low: math.Inf(-1), high: math.Inf(1)},
+		{wins: 5, losses: 5, diff: 0, low: -203.50, high: 203.50},
+		{draws: 10, diff: 0, low: -203.50, high: 203.50},
+		{wins: 30, draws: 10, losses: 10, diff: 147.19, low: 43.66, high: 250.73},
+		{wins: 10, diff: math.Inf(1), low: 166.20, high: math.Inf(1)},
+		{losses: 10, diff: math.Inf(-1), low: math.Inf(-1), high: -166.20},
+		{wins: 1, diff: math.Inf(1), low: -233.80, high: math.Inf(1)},
+	}
+	for _, tt := range tests {
+		r := record{wins: tt.wins, draws: tt.draws, losses: tt.losses}
+		diff, low, high := r.elo()
+		if !closeTo(diff, tt.diff) || !closeTo(low, tt.low) || !closeTo(high, tt.high) {
+			t.Errorf("elo() of %d-%d-%d got %v (%v to %v), want %v (%v to %v)",
+				tt.wins, tt.draws, tt.losses, diff, low, high, tt.diff, tt.low, tt.high)
+		}
+	}
+}
+
+func TestRecordAdd(t *testing.T) {
+	var r record
+	r = r.add(common.Player1, 10)
+	r = r.add(0, 0)
+	r = r.add(common.Player2, -4)
+
+	if r.wins != 1 || r.draws != 1 || r.losses != 1 || r.games() != 3 {
+		t.Errorf("add() got %d-%d-%d, want 1-1-1", r.wins, r.draws, r.losses)
+	}
+	if got := r.score(); got != 0.5 {
+		t.Errorf("score() got %v, want 0.5", got)
+	}
+	if got := r.averageDisks(); got != 2 {
+		t.Errorf("averageDisks() got %v, want 2", got)
+	}
+}
+
+func TestFormatElo(t *testing.T) {
+	tests := []struct {
+		elo  float64
+		want string
+	}{
+		{elo: math.Inf(1), want: "+inf"},
+		{elo: math.Inf(-1), want: "-inf"},
+		{elo: 0, want: "+0"},
+		{elo: math.Copysign(0, -1), want: "+0"},
+		{elo: 190.85, want: "+191"},
+		{elo: -190.85, want: "-191"},
+	}
+	for _, tt := range tests {
+		if got := formatElo(tt.elo); got != tt.want {
+			t.Errorf("formatElo(%v) got %q, want %q", tt.elo, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Command arena plays AI engines against each other to measure which is stronger, without the
+// server.
+//
+// Usage:
+//
+//	arena [-games n] [-plies n] [-size n] [-variant name] [-seed n] [-parallel n] player player ...
+//
+// Each player is an engine name, optionally followed by a colon and a difficulty level of 0 (easy),
+// 1 (normal) or 2 (hard), such as "book:2" or "minimax:0". The difficulty defaults to normal. Every
+// pair of players plays -games games. Each game starts from a random opening of -plies moves, and
+// each opening is played twice, with the players swapping colors, so that neither player is helped
+// by a lucky opening.
+//
+// For each pair, the wins, draws and losses of the first player are printed, along with the
+// average disk differential and the Elo difference between the players, with a 95% confidence
+// interval. The result of each game is logged as it finishes.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/ai"
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func main() {
+	games := flag.Int("games", 20, "Number of games that each pair of players plays. Rounded up to an even number.")
+	plies := flag.Int("plies", 4, "Number of random moves of each opening.")
+	size := flag.Int("size", common.DefaultBoardSize, "Board size.")
+	variant := flag.String("variant", "", `Rules of the games, such as "anti". Defaults to the standard game.`)
+	seed := flag.Int64("seed", time.Now().UnixNano(), "Seed of the random openings and of the moves chosen from opening books. Defaults to the clock.")
+	parallel := flag.Int("parallel", runtime.NumCPU(), "Number of games to play at once.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-games n] [-plies n] [-size n] [-variant name] [-seed n] [-parallel n] player player ...\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Engines: %s\n", strings.Join(ai.Engines(), ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 || *games < 1 || *plies < 0 || *parallel < 1 ||
+		!common.ValidBoardSize(*size) || !common.ValidVariant(common.Variant(*variant)) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	players := make([]player, flag.NArg())
+	for i, spec := range flag.Args() {
+		p, err := parsePlayer(spec, common.Variant(*variant))
+		if err != nil {
+			log.Fatal(err)
+		}
+		players[i] = p
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	log.Printf("Playing openings of seed %d", *seed)
+
+	// Every pair plays the same openings, so that the pairs can be compared.
+	openings := make([]string, (*games+1)/2)
+	for i := range openings {
+		openings[i] = randomOpening(rng, *size, *plies)
+	}
+
+	var matchups []matchup
+	for i := range players {
+		for j := i + 1; j < len(players); j++ {
+			for _, opening := range openings {
+				matchups = append(matchups,
+					matchup{a: i, b: j, opening: opening, seed: rng.Int63()},
+					matchup{a: i, b: j, opening: opening, seed: rng.Int63(), swapped: true},
+				)
+			}
+		}
+	}
+
+	records := play(context.Background(), players, matchups, *size, common.Variant(*variant), *parallel)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PLAYER\tOPPONENT\tGAMES\tWINS\tDRAWS\tLOSSES\tDISKS\tELO\t95% CI\t")
+	for i := range players {
+		for j := i + 1; j < len(players); j++ {
+			r := records[[2]int{i, j}]
+			elo, low, high := r.elo()
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%+.1f\t%s\t%s to %s\t\n",
+				players[i].name, players[j].name, r.games(), r.wins, r.draws, r.losses,
+				r.averageDisks(), formatElo(elo), formatElo(low), formatElo(high))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// player is an engine playing at a difficulty level.
+type player struct {
+	name   string
+	engine ai.Engine
+	limits ai.Limits
+}
+
+// parsePlayer reads a player from its name on the command line, such as "book:2".
+func parsePlayer(spec string, variant common.Variant) (player, error) {
+	name, difficulty := spec, 1
+
+	if i := strings.IndexByte(spec, ':'); i >= 0 {
+		var err error
+		name = spec[:i]
+		if difficulty, err = strconv.Atoi(spec[i+1:]); err != nil || difficulty < 0 || difficulty > 2 {
+			return player{}, fmt.Errorf("player %q has an invalid difficulty", spec)
+		}
+	}
+
+	engine, ok := ai.Lookup(name)
+	if !ok {
+		return player{}, fmt.Errorf("player %q has an unknown engine", spec)
+	}
+
+	// Each game searches with one CPU, since games are played in parallel instead.
+	limits := ai.DifficultyLimits(difficulty)
+	limits.Variant = variant
+
+	return player{name: spec, engine: engine, limits: limits}, nil
+}
+
+// withRand returns the player with its engine choosing moves from its book with rng, if it plays
+// from a book, so that the games can be played again with the same seed.
+func (p player) withRand(rng *rand.Rand) player {
+	if book, ok := p.engine.(ai.BookEngine); ok {
+		book.Rand = rng
+		p.engine = book
+	}
+	return p
+}
+
+// randomOpening returns the transcript of an opening of random moves that does not end the game.
+func randomOpening(rng *rand.Rand, size, plies int) string {
+	for {
+		match := common.NewMatch(size)
+		for len(match.History) < plies && !match.IsOver() {
+			moves := match.LegalMoves()
+			move := moves[rng.Intn(len(moves))]
+			_ = match.Play(move.X, move.Y)
+		}
+
+		if !match.IsOver() {
+			return match.Transcript()
+		}
+	}
+}
+
+// matchup is a game between the players at indexes a and b. Player a plays first, unless the
+// colors are swapped. The seed is the seed of the moves that the players choose from their books.
+type matchup struct {
+	a, b    int
+	opening string
+	seed    int64
+	swapped bool
+}
+
+// play plays the games of the matchups, the given number at a time, and returns the record of
+// each pair of players, from the perspective of the first player of the pair.
+func play(ctx context.Context, players []player, matchups []matchup, size int, variant common.Variant, parallel int) map[[2]int]record {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		records = make(map[[2]int]record)
+		queue   = make(chan matchup)
+	)
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for m := range queue {
+				first, second := players[m.a], players[m.b]
+				if m.swapped {
+					first, second = second, first
+				}
+
+				// Each game has its own source, since the games are played in any order.
+				rng := rand.New(rand.NewSource(m.seed))
+
+				result, err := playGame(ctx, m.opening, size, variant, first.withRand(rng), second.withRand(rng))
+				if err != nil {
+					log.Fatalf("%s vs %s from opening %q: %v", first.name, second.name, m.opening, err)
+				}
+
+				// The disk differential and winner, for player a.
+				disks := result.Scores[0] - result.Scores[1]
+				winner := result.Winner
+				if m.swapped {
+					disks = -disks
+					if winner != 0 {
+						winner = winner%2 + 1
+					}
+				}
+
+				log.Printf("%s vs %s from opening %q: %v", first.name, second.name, m.opening, result.Scores)
+
+				mu.Lock()
+				records[[2]int{m.a, m.b}] = records[[2]int{m.a, m.b}].add(winner, disks)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, m := range matchups {
+		queue <- m
+	}
+	close(queue)
+	wg.Wait()
+
+	return records
+}
+
+// playGame plays a game from the opening, where first plays player 1 and second plays player 2,
+// and returns its result.
+func playGame(ctx context.Context, opening string, size int, variant common.Variant, first, second player) (common.Result, error) {
+	match := common.NewMatch(size)
+	match.SetVariant(variant)
+	if err := match.PlayTranscript(opening); err != nil {
+		return common.Result{}, err
+	}
+
+	for !match.IsOver() {
+		p := first
+		if match.Player == common.Player2 {
+			p = second
+		}
+
+		move, err := p.engine.BestMove(ctx, match.Board, match.Player, p.limits)
+		if err != nil {
+			return common.Result{}, err
+		}
+
+		if err := match.Play(move[0], move[1]); err != nil {
+			return common.Result{}, fmt.Errorf("%s made an illegal move %s: %w", p.name, common.FormatSquare(size, move[0], move[1]), err)
+		}
+	}
+
+	return *match.Result, nil
+}
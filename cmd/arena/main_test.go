@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestPlayGameSeed(t *testing.T) {
+	p, err := parsePlayer("book:0", common.Standard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The easy AI stops at its maximum depth long before its time runs out, so only the book
+	// could make the games differ.
+	play := func(seed int64) []common.Turn {
+		rng := rand.New(rand.NewSource(seed))
+		match := common.NewMatch(common.DefaultBoardSize)
+		for i := 0; i < 6; i++ {
+			move, err := p.withRand(rng).engine.BestMove(context.Background(), match.Board, match.Player, p.limits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := match.Play(move[0], move[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return match.History
+	}
+
+	for seed := int64(0); seed < 5; seed++ {
+		if first, second := play(seed), play(seed); !reflect.DeepEqual(first, second) {
+			t.Errorf("games with seed %d got moves %v and %v, want the same moves", seed, first, second)
+		}
+	}
+
+	if _, err := playGame(context.Background(), "f5", common.DefaultBoardSize, common.Standard, p.withRand(rand.New(rand.NewSource(1))), p.withRand(rand.New(rand.NewSource(1)))); err != nil {
+		t.Errorf("playGame() got error %v", err)
+	}
+}
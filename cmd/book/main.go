@@ -0,0 +1,119 @@
+// Command book builds an opening book for the AI from a set of game transcripts.
+//
+// Usage:
+//
+//	book [-o output] [-plies n] [-min n] [file ...]
+//
+// The transcripts are read one per line, such as "f5d6c3d3c4", from the given files or from stdin.
+// Blank lines are ignored. The first moves of each game are added to the book, and the weight of a
+// move is the number of games that played it. Moves that were played by fewer than -min games are
+// left out, so that the book only keeps the openings that are played often. The book is written
+// in the format that ai.ReadBook reads.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/armsnyder/othelgo/pkg/ai"
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func main() {
+	output := flag.String("o", "", "Output file. Defaults to stdout.")
+	plies := flag.Int("plies", 12, "Number of moves of each game to add to the book.")
+	minWeight := flag.Int("min", 1, "Minimum number of games that must play a move for it to be in the book.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-o output] [-plies n] [-min n] [file ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *plies < 1 || *minWeight < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	book := ai.NewBook()
+
+	if flag.NArg() == 0 {
+		if err := addGames(book, os.Stdin, *plies); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for _, name := range flag.Args() {
+		if err := addFile(book, name, *plies); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	book.Prune(*minWeight)
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := book.Write(out); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Wrote %d positions", book.Len())
+}
+
+// addFile adds the games of a file of transcripts to the book.
+func addFile(book *ai.Book, name string, plies int) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := addGames(book, f, plies); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+// addGames adds the first moves of each game of the transcripts, one per line, to the book.
+func addGames(book *ai.Book, r io.Reader, plies int) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		game, err := common.ParseTranscript(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		// Replay the game, adding each move to the book from the position it was played in.
+		match := common.NewMatch(common.DefaultBoardSize)
+		for _, turn := range game.History {
+			if len(match.History) >= plies {
+				break
+			}
+			if turn.Pass {
+				continue
+			}
+
+			book.Add(match.Board, match.Player, turn.X, turn.Y, 1)
+			_ = match.Play(turn.X, turn.Y)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,67 @@
+// Command perft counts the positions that can be reached from the opening position at each depth,
+// to check the move generator against published counts and measure its speed.
+//
+// Usage:
+//
+//	perft [-size n] [-players n] [-board text] [depth]
+//
+// The counts are printed for every depth from 1 up to the given depth, which defaults to 8. The
+// -board flag starts from a position in the compact text form of common.ParseBoard, such as
+// "8/8/8/3xo3/3ox3/8/8/8 x", instead of the opening position.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func main() {
+	size := flag.Int("size", common.DefaultBoardSize, "Board size.")
+	players := flag.Int("players", 2, "Number of players.")
+	text := flag.String("board", "", "Starting position. Overrides -size and -players.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-size n] [-players n] [-board text] [depth]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	depth := 8
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if flag.NArg() == 1 {
+		var err error
+		if depth, err = strconv.Atoi(flag.Arg(0)); err != nil || depth < 1 {
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	if !common.ValidBoardSize(*size) || *players < 2 || *players > common.MaxPlayers {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	board, player := common.NewMatchForPlayers(*size, *players).Board, common.Player1
+	if *text != "" {
+		var err error
+		if board, player, err = common.ParseBoard(*text); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for d := 1; d <= depth; d++ {
+		start := time.Now()
+		nodes := common.Perft(board, player, d)
+		elapsed := time.Since(start)
+
+		fmt.Printf("depth %2d  nodes %14d  time %12v  %6.1f Mnodes/s\n", d, nodes, elapsed.Round(time.Microsecond), float64(nodes)/elapsed.Seconds()/1e6)
+	}
+}
@@ -0,0 +1,128 @@
+// Command wthor converts WTHOR game databases to and from move transcripts.
+//
+// Usage:
+//
+//	wthor [-o output] totext [file.wtb]
+//	wthor [-o output] fromtext [file.txt]
+//
+// totext prints each game of a WTHOR file as a transcript, such as "f5d6c3d3c4", one per line.
+// fromtext reads transcripts, one per line, and writes them as a WTHOR file. Input is read from
+// stdin if no file is given.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+	"github.com/armsnyder/othelgo/pkg/wthor"
+)
+
+func main() {
+	output := flag.String("o", "", "Output file. Defaults to stdout.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-o output] totext|fromtext [file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 || flag.NArg() > 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var convert func(io.Reader, io.Writer) error
+	switch flag.Arg(0) {
+	case "totext":
+		convert = toText
+	case "fromtext":
+		convert = fromText
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	in := os.Stdin
+	if flag.NArg() == 2 {
+		f, err := os.Open(flag.Arg(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+
+	if err := convert(bufio.NewReader(in), w); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// toText writes each game of a WTHOR file as a transcript. Games with illegal moves are skipped.
+func toText(r io.Reader, w io.Writer) error {
+	db, err := wthor.Read(r)
+	if err != nil {
+		return err
+	}
+
+	for i, game := range db.Games {
+		match, err := game.Match()
+		if err != nil {
+			log.Printf("Skipping game %d: %v", i+1, err)
+			continue
+		}
+
+		if _, err := fmt.Fprintln(w, match.Transcript()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fromText writes a WTHOR file of the transcripts read one per line. Blank lines are ignored.
+func fromText(r io.Reader, w io.Writer) error {
+	now := time.Now()
+	db := wthor.Database{Created: now, Year: now.Year()}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		match, err := common.ParseTranscript(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		db.Games = append(db.Games, wthor.NewGame(match))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return wthor.Write(w, db)
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// MoveScore is a legal move along with how good it is for the player who makes it, as judged by a
+// search.
+type MoveScore struct {
+	Move  [2]int
+	Score float64
+}
+
+// Analyze scores every legal move of the player, by searching each of them with the same limits
+// as Search. Unlike Search, which only proves that the other moves are worse than the best one,
+// every move gets its true score, so the moves are slower to search. The moves are returned from
+// best to worst, so the first move is the one to recommend.
+func (Minimax) Analyze(ctx context.Context, board common.Board, player common.Disk, limits Limits) ([]MoveScore, error) {
+	if !common.HasMoves(board, player) {
+		return nil, ErrNoMoves
+	}
+
+	if empties := board.Empties(); limits.Depth <= 0 || limits.Depth > empties {
+		limits.Depth = empties
+	}
+
+	deadlineCtx := ctx
+	if limits.Time > 0 {
+		var cancel context.CancelFunc
+		deadlineCtx, cancel = context.WithTimeout(ctx, limits.Time)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	state := newGameState(board, player, common.PlayerCount(board), limits.Variant, limits.Evaluation)
+	table := newTranspositionTable(defaultTableEntries)
+
+	// Search all of the moves one turn ahead, then two, and so on, like iterativeDeepening. The
+	// first depth is always allowed to finish, so that there are scores to return.
+	var scores []MoveScore
+
+	for depth := 1; depth <= limits.Depth; depth++ {
+		searchCtx := deadlineCtx
+		if scores == nil {
+			searchCtx = ctx
+		}
+
+		s := &search{ctx: searchCtx, table: table}
+
+		result := make([]MoveScore, state.MoveCount())
+		for i := range result {
+			result[i].Move = state.MoveLocation(i)
+			result[i].Score = s.childScore(state, state.Move(i), depth-1, math.Inf(-1), math.Inf(1), nil)
+		}
+
+		if s.stopped {
+			break
+		}
+
+		scores = result
+	}
+
+	if scores == nil {
+		return nil, ctx.Err()
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores, nil
+}
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestMinimaxAnalyze(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, tt := range []struct{ size, players int }{{8, 2}, {6, 2}, {8, 3}} {
+		match := common.NewMatchForPlayers(tt.size, tt.players)
+		playRandomMoves(t, rng, &match, 10)
+		if match.IsOver() {
+			continue
+		}
+
+		scores, err := Minimax{}.Analyze(context.Background(), match.Board, match.Player, Limits{Depth: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(scores) != len(match.LegalMoves()) {
+			t.Fatalf("Analyze() on a board of size %d with %d players got %d moves, want %d", tt.size, tt.players, len(scores), len(match.LegalMoves()))
+		}
+
+		// Every move is scored as deeply as the best move of a plain minimax search would be.
+		state := newGameState(match.Board, match.Player, tt.players, common.Standard, FullEvaluation)
+		for i := 0; i < state.MoveCount(); i++ {
+			want := plainMinimax(state.Move(i), 3)
+			for _, score := range scores {
+				if score.Move == state.MoveLocation(i) && score.Score != want {
+					t.Errorf("Analyze() on a board of size %d with %d players got score %f for move %v, want %f", tt.size, tt.players, score.Score, score.Move, want)
+				}
+			}
+		}
+
+		for i := 1; i < len(scores); i++ {
+			if scores[i].Score > scores[i-1].Score {
+				t.Errorf("Analyze() got move %v with score %f after a worse move", scores[i].Move, scores[i].Score)
+			}
+		}
+	}
+}
+
+func TestMinimaxAnalyzeNoMoves(t *testing.T) {
+	board := common.NewBoard(common.DefaultBoardSize)
+	board.Set(0, 0, common.Player1)
+
+	if _, err := (Minimax{}).Analyze(context.Background(), board, common.Player1, Limits{Depth: 4}); !errors.Is(err, ErrNoMoves) {
+		t.Errorf("Analyze() got error %v, want %v", err, ErrNoMoves)
+	}
+}
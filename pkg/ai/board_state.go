@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"math"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// boardState implements the othelgo domain-specific logic needed by the AI for games that
+// do not fit in a bitboard. It is slower than bitboardState but works for any board size and any
+// number of players.
+//
+// In games of more than two players, the AI assumes that all of the other players are working
+// together against it, and measures itself against whichever of them is doing best, which is the
+// one with the most disks, or the fewest in Anti-Othello.
+type boardState struct {
+	board            common.Board
+	players          int
+	variant          common.Variant
+	turn             common.Disk
+	maximizingPlayer common.Disk
+	moves            []common.Board
+	moveLocations    [][2]int
+}
+
+func (a *boardState) Score() float64 {
+	scores := common.Scores(a.board, a.players)
+	opponent := a.strongestOpponent(scores)
+	own, best := scores[a.maximizingPlayer-1], scores[opponent-1]
+
+	if common.GameOver(a.board) {
+		switch a.variant.Winner(scores) {
+		case a.maximizingPlayer:
+			return math.Inf(1)
+		case 0:
+			// A draw between other players is still a loss for the AI.
+			if own == best {
+				return 0
+			}
+			return math.Inf(-1)
+		default:
+			return math.Inf(-1)
+		}
+	}
+
+	trueScoreDelta := float64(own - best)
+	scoreModifier := a.scoreModifier(a.maximizingPlayer) - a.scoreModifier(opponent)
+
+	// Modifier strength decreases as the board fills up.
+	scoreModifier *= a.percentFull(scores)
+
+	// The score only counts disks, edges and corners, which are all bad to hold in Anti-Othello.
+	return diskSign(a.variant) * (trueScoreDelta + scoreModifier)
+}
+
+// strongestOpponent returns the opponent of the maximizing player who is closest to winning, which
+// is the one with the most disks, or the fewest in Anti-Othello.
+func (a *boardState) strongestOpponent(scores []int) common.Disk {
+	var opponent common.Disk
+	sign := diskSign(a.variant)
+
+	for player := common.Player1; int(player) <= a.players; player++ {
+		if player != a.maximizingPlayer && (opponent == 0 || sign*float64(scores[player-1]) > sign*float64(scores[opponent-1])) {
+			opponent = player
+		}
+	}
+
+	return opponent
+}
+
+func (a *boardState) scoreModifier(player common.Disk) (score float64) {
+	endIndex := a.board.Size() - 1
+
+	// Edges are valuable.
+	edgeScore := 0.5
+	for i := 1; i < endIndex; i++ {
+		for _, square := range [][2]int{{i, 0}, {0, i}, {i, endIndex}, {endIndex, i}} {
+			if a.board.At(square[0], square[1]) == player {
+				score += edgeScore
+			}
+		}
+	}
+
+	// Corners are highly valuable.
+	cornerScore := float64(2)
+	for _, square := range [][2]int{{0, 0}, {0, endIndex}, {endIndex, 0}, {endIndex, endIndex}} {
+		if a.board.At(square[0], square[1]) == player {
+			score += cornerScore
+		}
+	}
+
+	return score
+}
+
+func (a *boardState) percentFull(scores []int) float64 {
+	size := a.board.Size()
+	freeCells := size * size
+	for _, score := range scores {
+		freeCells -= score
+	}
+	return float64(freeCells) / float64(size*size)
+}
+
+func (a *boardState) Key() uint64 {
+	return a.board.Key(a.turn)
+}
+
+func (a *boardState) AITurn() bool {
+	return a.turn == a.maximizingPlayer
+}
+
+func (a *boardState) MoveCount() int {
+	if a.moves == nil {
+		a.moves = []common.Board{}
+		for x := 0; x < a.board.Size(); x++ {
+			for y := 0; y < a.board.Size(); y++ {
+				if board, updated := common.ApplyMove(a.board, x, y, a.turn); updated {
+					a.moves = append(a.moves, board)
+					a.moveLocations = append(a.moveLocations, [2]int{x, y})
+				}
+			}
+		}
+	}
+
+	return len(a.moves)
+}
+
+func (a *boardState) MoveLocation(i int) [2]int {
+	a.MoveCount() // Lazy initialize moves
+
+	return a.moveLocations[i]
+}
+
+func (a *boardState) Corner(i int) bool {
+	a.MoveCount() // Lazy initialize moves
+
+	end := a.board.Size() - 1
+	x, y := a.moveLocations[i][0], a.moveLocations[i][1]
+	return (x == 0 || x == end) && (y == 0 || y == end)
+}
+
+func (a *boardState) Move(i int) GameState {
+	a.MoveCount() // Lazy initialize moves
+
+	nextState := &boardState{
+		board:            a.moves[i],
+		players:          a.players,
+		variant:          a.variant,
+		turn:             a.turn,
+		maximizingPlayer: a.maximizingPlayer,
+	}
+
+	// Players who cannot move are skipped. If nobody else can move, the turn stays with the
+	// player who just moved.
+	for player := common.NextPlayer(a.turn, a.players); player != a.turn; player = common.NextPlayer(player, a.players) {
+		if common.HasMoves(a.moves[i], player) {
+			nextState.turn = player
+			break
+		}
+	}
+
+	return nextState
+}
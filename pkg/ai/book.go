@@ -0,0 +1,317 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// A book file lists positions and the moves to play from them, one position per line. Each line
+// is a board in the compact text form of common.ParseBoard, including the player to move, followed
+// by the moves in transcript notation, each with a weight after a colon. A move is chosen with a
+// probability in proportion to its weight. For example:
+//
+//	8/8/8/3xo3/3ox3/8/8/8 x f5:4 d6:2
+//
+// Blank lines are ignored.
+
+// BookMove is a move of a position in a Book, with its weight.
+type BookMove struct {
+	X, Y   int
+	Weight int
+}
+
+// Book is an opening book, which knows good moves for positions near the start of the game.
+// Positions are stored in their canonical form, so a move that is known for a position is also
+// known for all of its rotations and reflections.
+type Book struct {
+	positions map[uint64]*bookPosition
+}
+
+// bookPosition is a canonical position of a Book and the weights of its moves.
+type bookPosition struct {
+	board  common.Board
+	player common.Disk
+	moves  map[[2]int]int
+}
+
+// NewBook returns an empty Book.
+func NewBook() *Book {
+	return &Book{positions: make(map[uint64]*bookPosition)}
+}
+
+// ReadBook reads a Book from a book file.
+func ReadBook(r io.Reader) (*Book, error) {
+	book := NewBook()
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %d: want a board, a player and at least one move", line)
+		}
+
+		board, player, err := common.ParseBoard(fields[0] + " " + fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		for _, field := range fields[2:] {
+			x, y, weight, err := parseBookMove(board, player, field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			book.Add(board, player, x, y, weight)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+// parseBookMove reads a move and its weight, such as "f5:4", and checks that the move is legal.
+func parseBookMove(board common.Board, player common.Disk, field string) (x, y, weight int, err error) {
+	i := strings.IndexByte(field, ':')
+	if i < 0 {
+		return 0, 0, 0, fmt.Errorf("move %q has no weight", field)
+	}
+
+	if x, y, err = common.ParseSquare(board.Size(), field[:i]); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if weight, err = strconv.Atoi(field[i+1:]); err != nil || weight <= 0 {
+		return 0, 0, 0, fmt.Errorf("move %q has an invalid weight", field)
+	}
+
+	if _, ok := common.ApplyMove(board, x, y, player); !ok {
+		return 0, 0, 0, fmt.Errorf("move %q is not legal", field)
+	}
+
+	return x, y, weight, nil
+}
+
+// Write writes the Book as a book file. Positions are written in order of the number of disks on
+// the board, so the file reads from the start of the game onwards.
+func (b *Book) Write(w io.Writer) error {
+	positions := make([]*bookPosition, 0, len(b.positions))
+	lines := make(map[*bookPosition]string, len(b.positions))
+	for _, position := range b.positions {
+		positions = append(positions, position)
+		lines[position] = position.String()
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		if ei, ej := positions[i].board.Empties(), positions[j].board.Empties(); ei != ej {
+			return ei > ej
+		}
+		return lines[positions[i]] < lines[positions[j]]
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, position := range positions {
+		if _, err := fmt.Fprintln(bw, lines[position]); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (p *bookPosition) String() string {
+	moves := make([][2]int, 0, len(p.moves))
+	for move := range p.moves {
+		moves = append(moves, move)
+	}
+
+	// Write the most likely moves first.
+	sort.Slice(moves, func(i, j int) bool {
+		if wi, wj := p.moves[moves[i]], p.moves[moves[j]]; wi != wj {
+			return wi > wj
+		}
+		return moves[i][1]*p.board.Size()+moves[i][0] < moves[j][1]*p.board.Size()+moves[j][0]
+	})
+
+	var sb strings.Builder
+	sb.WriteString(common.FormatBoard(p.board, p.player))
+	for _, move := range moves {
+		fmt.Fprintf(&sb, " %s:%d", common.FormatSquare(p.board.Size(), move[0], move[1]), p.moves[move])
+	}
+
+	return sb.String()
+}
+
+// Add adds weight to the move (x, y) of the player in the position, adding the position to the
+// Book if it is not there yet.
+func (b *Book) Add(board common.Board, player common.Disk, x, y, weight int) {
+	canonical, symmetry := board.Canonical()
+	key := canonical.Key(player)
+
+	position, ok := b.positions[key]
+	if !ok {
+		position = &bookPosition{board: canonical, player: player, moves: make(map[[2]int]int)}
+		b.positions[key] = position
+	}
+
+	cx, cy := symmetry.Square(board.Size(), x, y)
+	position.moves[[2]int{cx, cy}] += weight
+}
+
+// Prune removes the moves with less than the given weight, and the positions that are left with
+// no moves.
+func (b *Book) Prune(minWeight int) {
+	for key, position := range b.positions {
+		for move, weight := range position.moves {
+			if weight < minWeight {
+				delete(position.moves, move)
+			}
+		}
+
+		if len(position.moves) == 0 {
+			delete(b.positions, key)
+		}
+	}
+}
+
+// Len returns the number of positions in the Book.
+func (b *Book) Len() int {
+	return len(b.positions)
+}
+
+// Moves returns the moves of the player in the position that are in the Book, in the order of
+// their squares, or nil if the position is not in the Book.
+//
+// A position that is symmetric, such as the opening, has moves that are equivalent to each other,
+// but the Book only stores one of them. The weight of each move is spread to all of the moves that
+// are equivalent to it, so that the AI does not always play the same one. Each move gets the weight
+// once for each symmetry that maps the stored move onto it, which keeps the total weight of the
+// equivalent moves in proportion to the weight in the Book.
+func (b *Book) Moves(board common.Board, player common.Disk) []BookMove {
+	canonical, _ := board.Canonical()
+
+	position, ok := b.positions[canonical.Key(player)]
+	if !ok {
+		return nil
+	}
+
+	weights := make(map[[2]int]int)
+	for _, symmetry := range common.Symmetries {
+		if board.Transform(symmetry) != canonical {
+			continue
+		}
+
+		inverse := symmetry.Inverse()
+		for move, weight := range position.moves {
+			x, y := inverse.Square(board.Size(), move[0], move[1])
+			weights[[2]int{x, y}] += weight
+		}
+	}
+
+	moves := make([]BookMove, 0, len(weights))
+	for move, weight := range weights {
+		moves = append(moves, BookMove{X: move[0], Y: move[1], Weight: weight})
+	}
+
+	// Sort the moves, so that a move chosen with the same random numbers is always the same.
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i].Y*board.Size()+moves[i].X < moves[j].Y*board.Size()+moves[j].X
+	})
+
+	return moves
+}
+
+// bookRand chooses the moves of a Book when no other source of randomness is given. It is seeded
+// from the clock, so that games start differently every time.
+var (
+	bookRandMu sync.Mutex
+	bookRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// Choose picks one of the moves of the player in the position at random, with a probability in
+// proportion to its weight. It returns false if the position is not in the Book. If rng is nil,
+// a shared source that is seeded from the clock is used.
+func (b *Book) Choose(board common.Board, player common.Disk, rng *rand.Rand) ([2]int, bool) {
+	moves := b.Moves(board, player)
+	if len(moves) == 0 {
+		return [2]int{}, false
+	}
+
+	total := 0
+	for _, move := range moves {
+		total += move.Weight
+	}
+
+	var n int
+	if rng != nil {
+		n = rng.Intn(total)
+	} else {
+		bookRandMu.Lock()
+		n = bookRand.Intn(total)
+		bookRandMu.Unlock()
+	}
+
+	for _, move := range moves {
+		if n < move.Weight {
+			return [2]int{move.X, move.Y}, true
+		}
+		n -= move.Weight
+	}
+
+	panic("unreachable")
+}
+
+// BookEngine is an Engine that plays from an opening book while it can, and asks another engine
+// for moves once the game leaves the book. The book is only used for games of standard rules.
+type BookEngine struct {
+	Book     *Book
+	Fallback Engine
+
+	// Rand chooses among the moves of the book. If it is nil, a shared source that is seeded from
+	// the clock is used. A rand.Rand is not safe for concurrent use.
+	Rand *rand.Rand
+}
+
+func (e BookEngine) BestMove(ctx context.Context, board common.Board, player common.Disk, limits Limits) ([2]int, error) {
+	if e.Book != nil && limits.Variant == common.Standard {
+		if move, ok := e.Book.Choose(board, player, e.Rand); ok {
+			return move, nil
+		}
+	}
+
+	return e.Fallback.BestMove(ctx, board, player, limits)
+}
+
+var (
+	defaultBook     *Book
+	defaultBookOnce sync.Once
+)
+
+// DefaultBook returns the opening book that is built into othelgo, which covers the first few
+// moves of the well-known openings.
+func DefaultBook() *Book {
+	defaultBookOnce.Do(func() {
+		book, err := ReadBook(strings.NewReader(defaultBookText))
+		if err != nil {
+			panic(fmt.Errorf("ai: invalid default book: %w", err))
+		}
+		defaultBook = book
+	})
+
+	return defaultBook
+}
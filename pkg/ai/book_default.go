@@ -0,0 +1,35 @@
+package ai
+
+// defaultBookText is the book file of DefaultBook. It was built with cmd/book from the first moves
+// of popular openings, weighted by how many of those openings play each move.
+const defaultBookText = `8/8/8/3xo3/3ox3/8/8/8 x f5:19
+8/8/8/3ox3/3xx3/4x3/8/8 o f5:11 f3:6 d3:2
+8/8/5o2/3xox2/3ox3/8/8/8 x e6:6
+8/8/8/3xo3/2xxo3/4o3/8/8 x f6:6 f4:3 f5:2
+8/8/8/3xo3/3xo3/3xo3/8/8 x f4:2
+8/8/2ox4/2xxx3/3xo3/8/8/8 o e6:5 c4:1
+8/8/3ox3/2xxx3/3ox3/8/8/8 o f6:1 f4:1
+8/8/3x4/3xx3/2oox3/4x3/8/8 o d7:1 f4:1
+8/8/4x3/3oxo2/3xx3/4x3/8/8 o d3:3
+8/8/4x3/3xx3/3xoo2/2x5/8/8 o c4:6
+8/8/2ooo3/3oxx2/3xo3/8/8/8 x f4:1
+8/8/3o4/3oxx2/3ox3/2xo4/8/8 x c4:6
+8/8/3x4/2oxo3/3oo3/3xo3/8/8 x f4:2 f5:1
+8/8/4x3/3xx3/2oooo2/3x4/8/8 x e3:1
+8/8/8/3ox3/3oxx2/3ooo2/8/8 x c4:2 c6:1 e2:1 f2:1
+8/8/2ox4/2oxx3/2oxo3/3x4/8/8 o e6:1 f5:1
+8/8/3x4/2oxx3/3oox2/3xo3/8/8 o c6:1 c3:1
+8/8/4x3/3xx3/2oxoo2/2xx4/8/8 o d6:5 c2:1
+8/8/2ox4/2oooo2/2oxo3/3x4/8/8 x f4:1
+8/8/3o4/3oox2/2xxxo2/2xo4/8/8 x f6:2 c5:2 e6:1
+8/8/3x4/2oox3/2xoox2/4oo2/8/8 x e6:1
+8/8/2ox4/2ooxx2/3oxx2/3xo3/8/8 o c3:1
+8/8/2xo4/2xxxo2/2xxxx2/3o4/8/8 o b6:2
+8/8/2xxo3/3xx3/2ooxo2/4xx2/8/8 o d7:1 f6:1
+8/8/2xo1o2/2xxoo2/3oxx2/3o1x2/8/8 x e3:1
+8/8/4o3/2xxxx2/2oxxx2/4ooo1/8/8 x f2:2
+8/5o2/3xxxx1/2oxxx2/3xx3/3xo3/8/8 o c4:1
+8/8/2xxoo2/2xxo3/2oxxo2/4xx2/8/8 o b5:1
+8/8/3ox3/3xxo2/2xxoo2/1xxox3/2o5/8 x f3:1
+8/8/2xoo3/2xxxx2/2oxxo2/1oxx4/2x5/8 o b4:1
+`
@@ -0,0 +1,172 @@
+package ai
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+const testBookText = `8/8/8/3xo3/3ox3/8/8/8 x f5:3 e6:1
+8/8/8/3xxx2/3ox3/8/8/8 o f6:2 d6:1 f4:1
+`
+
+func TestReadBook(t *testing.T) {
+	book, err := ReadBook(strings.NewReader(testBookText))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if book.Len() != 2 {
+		t.Errorf("ReadBook() got %d positions, want 2", book.Len())
+	}
+
+	// The opening is symmetric, so writing the book may turn the squares around, but reading it
+	// back must give the same book.
+	var sb strings.Builder
+	if err := book.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	reread, err := ReadBook(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb2 strings.Builder
+	if err := reread.Write(&sb2); err != nil {
+		t.Fatal(err)
+	}
+
+	if sb.String() != sb2.String() {
+		t.Errorf("Write() after ReadBook() got:\n%s\nwant:\n%s", sb2.String(), sb.String())
+	}
+}
+
+func TestReadBookErrors(t *testing.T) {
+	for _, text := range []string{
+		"8/8/8/3xo3/3ox3/8/8/8 x",
+		"8/8/8/3xo3/3ox3/8/8/8 x f5",
+		"8/8/8/3xo3/3ox3/8/8/8 x f5:0",
+		"8/8/8/3xo3/3ox3/8/8/8 x a1:1",
+		"8/8/8/3xo3/3ox3/8/8/8 x z9:1",
+		"8/8/8 x f5:1",
+	} {
+		if _, err := ReadBook(strings.NewReader(text)); err == nil {
+			t.Errorf("ReadBook(%q) got no error", text)
+		}
+	}
+}
+
+func TestBookSymmetry(t *testing.T) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	_ = match.PlayTranscript("f5")
+
+	book := NewBook()
+	book.Add(match.Board, match.Player, 3, 2, 1) // d6
+
+	for _, s := range common.Symmetries {
+		board := match.Board.Transform(s)
+		x, y := s.Square(common.DefaultBoardSize, 3, 2)
+
+		moves := book.Moves(board, match.Player)
+		if len(moves) != 1 || moves[0].X != x || moves[0].Y != y {
+			t.Errorf("Moves() of the board transformed by %d got %v, want (%d, %d)", s, moves, x, y)
+		}
+	}
+
+	if moves := book.Moves(match.Board, common.Player1); moves != nil {
+		t.Errorf("Moves() for the wrong player got %v", moves)
+	}
+
+	// The opening is symmetric, so all four of its moves are the same move.
+	book = NewBook()
+	book.Add(common.NewMatch(common.DefaultBoardSize).Board, common.Player1, 5, 3, 1) // f5
+
+	moves := book.Moves(common.NewMatch(common.DefaultBoardSize).Board, common.Player1)
+	want := []BookMove{{X: 4, Y: 2, Weight: 1}, {X: 5, Y: 3, Weight: 1}, {X: 2, Y: 4, Weight: 1}, {X: 3, Y: 5, Weight: 1}}
+	if !reflect.DeepEqual(moves, want) {
+		t.Errorf("Moves() of the opening got %v, want %v", moves, want)
+	}
+}
+
+func TestBookChoose(t *testing.T) {
+	book, err := ReadBook(strings.NewReader(testBookText))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	board, player, _ := common.ParseBoard("8/8/8/3xxx2/3ox3/8/8/8 o")
+
+	counts := make(map[[2]int]int)
+	for i := 0; i < 4000; i++ {
+		move, ok := book.Choose(board, player, rng)
+		if !ok {
+			t.Fatal("Choose() of the position after f5 got ok = false")
+		}
+		counts[move]++
+	}
+
+	// f6 has as much weight as d6 and f4 together.
+	f6, d6, f4 := counts[[2]int{5, 2}], counts[[2]int{3, 2}], counts[[2]int{5, 4}]
+	if f6+d6+f4 != 4000 {
+		t.Fatalf("Choose() got moves %v, want only f6, d6 and f4", counts)
+	}
+	if ratio := float64(f6) / float64(d6+f4); ratio < 0.8 || ratio > 1.2 {
+		t.Errorf("Choose() got f6 %d times and d6 or f4 %d times, want about as many of each", f6, d6+f4)
+	}
+	if ratio := float64(d6) / float64(f4); ratio < 0.8 || ratio > 1.2 {
+		t.Errorf("Choose() got d6 %d times and f4 %d times, want about as many of each", d6, f4)
+	}
+
+	// Every opening move is the same, so each is chosen about as often.
+	counts = make(map[[2]int]int)
+	for i := 0; i < 4000; i++ {
+		move, _ := book.Choose(common.NewMatch(common.DefaultBoardSize).Board, common.Player1, rng)
+		counts[move]++
+	}
+	for _, move := range [][2]int{{5, 3}, {4, 2}, {2, 4}, {3, 5}} {
+		if n := counts[move]; n < 800 || n > 1200 {
+			t.Errorf("Choose() of the opening got %v %d times out of 4000, want about 1000", move, n)
+		}
+	}
+
+	if _, ok := book.Choose(common.NewBoard(6), common.Player1, rng); ok {
+		t.Errorf("Choose() of a board that is not in the book got ok = true")
+	}
+}
+
+func TestBookEngine(t *testing.T) {
+	engine := BookEngine{Book: DefaultBook(), Fallback: firstMoveEngine{}, Rand: rand.New(rand.NewSource(1))}
+	match := common.NewMatch(common.DefaultBoardSize)
+
+	move, err := engine.BestMove(context.Background(), match.Board, match.Player, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move != [2]int{5, 3} && move != [2]int{4, 2} && move != [2]int{2, 4} && move != [2]int{3, 5} {
+		t.Errorf("BestMove() of the opening got %v, want a move from the book", move)
+	}
+
+	// Out of the book, and in games of other rules, the fallback engine chooses.
+	want, _ := firstMoveEngine{}.BestMove(context.Background(), match.Board, match.Player, Limits{})
+	if move, _ := engine.BestMove(context.Background(), match.Board, match.Player, Limits{Variant: common.AntiOthello}); move != want {
+		t.Errorf("BestMove() of anti-othello got %v, want %v", move, want)
+	}
+
+	board := common.NewMatch(6).Board
+	want, _ = firstMoveEngine{}.BestMove(context.Background(), board, common.Player1, Limits{})
+	if move, _ := engine.BestMove(context.Background(), board, common.Player1, Limits{}); move != want {
+		t.Errorf("BestMove() out of the book got %v, want %v", move, want)
+	}
+}
+
+func TestDefaultBook(t *testing.T) {
+	if DefaultBook().Len() == 0 {
+		t.Errorf("DefaultBook() is empty")
+	}
+}
@@ -0,0 +1,128 @@
+// Package ai contains the computer players of othelgo. Each kind of computer player is an Engine,
+// and engines are registered by name so that they can be chosen when a game is started.
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// ErrNoMoves is returned by an Engine when the player has no legal moves.
+var ErrNoMoves = errors.New("the player has no legal moves")
+
+// Engine picks moves for a computer player.
+type Engine interface {
+	// BestMove returns the coordinates of the move that the engine chooses for the player, within
+	// the limits. The number of players in the game is the number of players on the board. It
+	// returns ErrNoMoves if the player has no legal moves, or the error of the context if it is
+	// done before a move is chosen.
+	BestMove(ctx context.Context, board common.Board, player common.Disk, limits Limits) ([2]int, error)
+}
+
+// Limits controls how hard an Engine works to choose a move.
+type Limits struct {
+	// Depth is the maximum number of turns to look ahead, or zero for no maximum.
+	Depth int
+
+	// Time is how long the engine may think about its move, or zero for no time limit. An engine
+	// may take less time, such as when it reaches the maximum Depth first. If neither Depth nor
+	// Time is set, the engine may take a very long time.
+	Time time.Duration
+
+	// SolveEndgame allows the engine to play perfectly once the end of the game is close enough to
+	// be solved exactly.
+	SolveEndgame bool
+
+	// Variant is the rules that the game is played by, which the engine needs to know in order to
+	// tell good moves from bad ones.
+	Variant common.Variant
+
+	// Workers is the number of goroutines that may search at once, for engines that can search in
+	// parallel. Zero means one, which makes the search deterministic.
+	Workers int
+
+	// Evaluation is how the engine scores the positions it looks at, for engines that support more
+	// than one way.
+	Evaluation Evaluation
+}
+
+// DifficultyLimits returns the limits of the AI at a difficulty level of a solo game, which is 0
+// for easy, 1 for normal or 2 for hard. Any other level is easy. Each difficulty has a time budget
+// for thinking about a move, and a maximum depth that keeps the easier AIs from playing too well
+// when they think quickly. The easy AI also judges positions naively.
+func DifficultyLimits(difficulty int) Limits {
+	var limits Limits
+
+	switch difficulty {
+	default:
+		limits.Time, limits.Depth = 250*time.Millisecond, 2
+		limits.Evaluation = SimpleEvaluation
+	case 1:
+		limits.Time, limits.Depth = 750*time.Millisecond, 5
+	case 2:
+		limits.Time, limits.Depth = 1500*time.Millisecond, 12
+		limits.SolveEndgame = true
+	}
+
+	return limits
+}
+
+// DefaultEngine is the name of the engine that is used when none is chosen. It plays from
+// DefaultBook and then searches with Minimax.
+const DefaultEngine = "book"
+
+var (
+	enginesMu sync.RWMutex
+	engines   = make(map[string]Engine)
+)
+
+func init() {
+	Register("minimax", Minimax{})
+	Register(DefaultEngine, BookEngine{Book: DefaultBook(), Fallback: Minimax{}})
+}
+
+// Register makes an engine available by name. It panics if the name is already taken, so that
+// engines cannot be replaced by accident.
+func Register(name string, engine Engine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+
+	if _, ok := engines[name]; ok {
+		panic(fmt.Errorf("ai: engine %q is already registered", name))
+	}
+
+	engines[name] = engine
+}
+
+// Lookup returns the engine with the given name, or DefaultEngine if the name is empty.
+func Lookup(name string) (Engine, bool) {
+	if name == "" {
+		name = DefaultEngine
+	}
+
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	engine, ok := engines[name]
+	return engine, ok
+}
+
+// Engines returns the names of the registered engines in alphabetical order.
+func Engines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
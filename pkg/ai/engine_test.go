@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// firstMoveEngine is an Engine that always plays the first legal move.
+type firstMoveEngine struct{}
+
+func (firstMoveEngine) BestMove(_ context.Context, board common.Board, player common.Disk, _ Limits) ([2]int, error) {
+	moves := common.LegalMoves(board, player)
+	if len(moves) == 0 {
+		return [2]int{}, ErrNoMoves
+	}
+	return [2]int{moves[0].X, moves[0].Y}, nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-first-move", firstMoveEngine{})
+
+	engine, ok := Lookup("test-first-move")
+	if !ok {
+		t.Fatalf("Lookup() of a registered engine got ok = false")
+	}
+	if _, ok := engine.(firstMoveEngine); !ok {
+		t.Errorf("Lookup() got engine %T, want firstMoveEngine", engine)
+	}
+
+	found := false
+	for _, name := range Engines() {
+		found = found || name == "test-first-move"
+	}
+	if !found {
+		t.Errorf("Engines() got %v, which does not include the registered engine", Engines())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() of a name that is already taken did not panic")
+		}
+	}()
+	Register("test-first-move", firstMoveEngine{})
+}
+
+func TestLookupDefault(t *testing.T) {
+	if engine, ok := Lookup(""); !ok || engine != (BookEngine{Book: DefaultBook(), Fallback: Minimax{}}) {
+		t.Errorf("Lookup() of no name got %v, %v, want the book engine", engine, ok)
+	}
+
+	if engine, ok := Lookup("minimax"); !ok || engine != (Minimax{}) {
+		t.Errorf("Lookup() of minimax got %v, %v, want the minimax engine", engine, ok)
+	}
+
+	if _, ok := Lookup("no-such-engine"); ok {
+		t.Errorf("Lookup() of an unknown engine got ok = true")
+	}
+}
@@ -0,0 +1,183 @@
+package ai
+
+import (
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// Evaluation is a way of scoring the positions that a search reaches. The zero value is the
+// strongest evaluation.
+type Evaluation string
+
+const (
+	// FullEvaluation weighs mobility, frontier disks, stable disks, corners and the squares next
+	// to them, and parity, differently in each phase of the game.
+	FullEvaluation = Evaluation("")
+
+	// SimpleEvaluation only counts disks, with small bonuses for edges and corners. It plays
+	// much more weakly than FullEvaluation.
+	SimpleEvaluation = Evaluation("simple")
+)
+
+// Masks of the squares next to the corners, which are dangerous to take while the corner is
+// empty, since they can give the corner away. X-squares are diagonally next to a corner, and
+// C-squares are next to a corner along an edge. Each list is in the same order as cornerList.
+var (
+	cornerList = [4]common.Bitboard{
+		common.SquareBit(0, 0),
+		common.SquareBit(7, 0),
+		common.SquareBit(0, 7),
+		common.SquareBit(7, 7),
+	}
+	xSquares = [4]common.Bitboard{
+		common.SquareBit(1, 1),
+		common.SquareBit(6, 1),
+		common.SquareBit(1, 6),
+		common.SquareBit(6, 6),
+	}
+	cSquares = [4]common.Bitboard{
+		common.SquareBit(1, 0) | common.SquareBit(0, 1),
+		common.SquareBit(6, 0) | common.SquareBit(7, 1),
+		common.SquareBit(0, 6) | common.SquareBit(1, 7),
+		common.SquareBit(7, 6) | common.SquareBit(6, 7),
+	}
+)
+
+// evalWeights are the weights of the features of FullEvaluation. Each feature is the difference
+// between the AI and its opponent.
+type evalWeights struct {
+	disks             float64 // Disks on the board.
+	mobility          float64 // Legal moves.
+	potentialMobility float64 // Empty squares next to the other player's disks.
+	frontier          float64 // Disks next to an empty square, which give the opponent moves.
+	corners           float64 // Corners.
+	xSquares          float64 // X-squares next to an empty corner.
+	cSquares          float64 // C-squares next to an empty corner.
+	stability         float64 // Disks that can never be flipped.
+	parity            float64 // Regions of empty squares where the AI can make the last move.
+}
+
+// The weights at the start and the end of the game. In between, the weights move from one to the
+// other as the board fills up. Early on, it is better to have few disks and many moves, while at
+// the end, only the disks matter.
+var (
+	openingWeights = evalWeights{
+		disks:             -0.25,
+		mobility:          2,
+		potentialMobility: 1,
+		frontier:          -1,
+		corners:           25,
+		xSquares:          -12,
+		cSquares:          -5,
+		stability:         3,
+		parity:            0,
+	}
+	endgameWeights = evalWeights{
+		disks:             1,
+		mobility:          0.5,
+		potentialMobility: 0,
+		frontier:          0,
+		corners:           8,
+		xSquares:          -2,
+		cSquares:          -1,
+		stability:         2,
+		parity:            3,
+	}
+)
+
+// openingEmpties is the number of empty squares of a standard board after the opening.
+const openingEmpties = common.DefaultBoardSize*common.DefaultBoardSize - 4
+
+// fullScore is the score of the position according to FullEvaluation, from the perspective of the
+// AI player. In Anti-Othello, the disk, corner and stability terms count against the AI, while
+// mobility, frontier and parity count the same as in the standard game.
+func (a *bitboardState) fullScore() float64 {
+	ai := a.maximizingPlayer
+	opponent := ai%2 + 1
+
+	own, opp := a.position.Disks(ai), a.position.Disks(opponent)
+	empty := a.position.Empty()
+
+	w := phaseWeights(empty.Count())
+	sign := diskSign(a.variant)
+
+	score := sign * w.disks * float64(own.Count()-opp.Count())
+
+	score += w.mobility * float64(a.position.LegalMoves(ai).Count()-a.position.LegalMoves(opponent).Count())
+
+	score += w.potentialMobility * float64((empty&opp.Neighbors()).Count()-(empty&own.Neighbors()).Count())
+
+	score += w.frontier * float64((own&empty.Neighbors()).Count()-(opp&empty.Neighbors()).Count())
+
+	for i, corner := range cornerList {
+		switch {
+		case own&corner != 0:
+			score += sign * w.corners
+		case opp&corner != 0:
+			score -= sign * w.corners
+		case empty&corner != 0:
+			score += sign * w.xSquares * float64((own&xSquares[i]).Count()-(opp&xSquares[i]).Count())
+			score += sign * w.cSquares * float64((own&cSquares[i]).Count()-(opp&cSquares[i]).Count())
+		}
+	}
+
+	score += sign * w.stability * float64(a.position.Stable(ai).Count()-a.position.Stable(opponent).Count())
+
+	if w.parity != 0 {
+		score += w.parity * float64(a.parity(empty))
+	}
+
+	return score
+}
+
+// phaseWeights returns the weights to use when the given number of squares are empty.
+func phaseWeights(empties int) evalWeights {
+	t := 1 - float64(empties)/openingEmpties
+	if t < 0 {
+		t = 0
+	}
+
+	mix := func(opening, endgame float64) float64 {
+		return opening + t*(endgame-opening)
+	}
+
+	return evalWeights{
+		disks:             mix(openingWeights.disks, endgameWeights.disks),
+		mobility:          mix(openingWeights.mobility, endgameWeights.mobility),
+		potentialMobility: mix(openingWeights.potentialMobility, endgameWeights.potentialMobility),
+		frontier:          mix(openingWeights.frontier, endgameWeights.frontier),
+		corners:           mix(openingWeights.corners, endgameWeights.corners),
+		xSquares:          mix(openingWeights.xSquares, endgameWeights.xSquares),
+		cSquares:          mix(openingWeights.cSquares, endgameWeights.cSquares),
+		stability:         mix(openingWeights.stability, endgameWeights.stability),
+		parity:            mix(openingWeights.parity, endgameWeights.parity),
+	}
+}
+
+// parity returns the number of regions of empty squares with an odd number of squares, which is
+// where the player to move can expect to make the last move, as a positive number if it is the
+// AI's turn or a negative number if it is the opponent's turn. Regions are connected in any of the
+// 8 directions.
+func (a *bitboardState) parity(empty common.Bitboard) int {
+	odd := 0
+
+	for empty != 0 {
+		region := empty & -empty
+		for {
+			grown := region | region.Neighbors()&empty
+			if grown == region {
+				break
+			}
+			region = grown
+		}
+
+		if region.Count()%2 == 1 {
+			odd++
+		}
+		empty &^= region
+	}
+
+	if a.AITurn() {
+		return odd
+	}
+	return -odd
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestFullEvaluationAvoidsXSquares(t *testing.T) {
+	// The same disk is worse next to an empty corner than away from it.
+	xSquare := newGameState(common.NewMatch(common.DefaultBoardSize).Board, common.Player2, 2, common.Standard, FullEvaluation)
+	other := newGameState(common.NewMatch(common.DefaultBoardSize).Board, common.Player2, 2, common.Standard, FullEvaluation)
+
+	xSquare.(*bitboardState).position.P1 |= common.SquareBit(1, 1)
+	other.(*bitboardState).position.P1 |= common.SquareBit(2, 5)
+
+	if xSquare.Score() <= other.Score() {
+		t.Errorf("Score() with an opponent on an X-square got %f, want more than %f", xSquare.Score(), other.Score())
+	}
+}
+
+func TestFullEvaluationBeatsSimple(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	wins := map[Evaluation]int{}
+
+	for game := 0; game < 8; game++ {
+		match := common.NewMatch(common.DefaultBoardSize)
+
+		// Start from a random opening, so that the games are different.
+		playRandomMoves(t, rng, &match, 4)
+
+		// Each evaluation plays each side of half of the games.
+		evaluations := [2]Evaluation{FullEvaluation, SimpleEvaluation}
+		if game%2 == 1 {
+			evaluations[0], evaluations[1] = evaluations[1], evaluations[0]
+		}
+
+		for !match.IsOver() {
+			limits := Limits{Depth: 3, Evaluation: evaluations[match.Player-1]}
+			move, err := Minimax{}.BestMove(context.Background(), match.Board, match.Player, limits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := match.Play(move[0], move[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if winner := match.Result.Winner; winner != 0 {
+			wins[evaluations[winner-1]]++
+		}
+	}
+
+	t.Logf("FullEvaluation won %d games and SimpleEvaluation won %d", wins[FullEvaluation], wins[SimpleEvaluation])
+
+	if wins[FullEvaluation] <= wins[SimpleEvaluation] {
+		t.Errorf("FullEvaluation won %d games and SimpleEvaluation won %d, want FullEvaluation to win more", wins[FullEvaluation], wins[SimpleEvaluation])
+	}
+}
@@ -0,0 +1,424 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// GameState represents the state of a game and implements game domain-specific logic.
+type GameState interface {
+	// Score evaluates the desirability of a state from the perspective of the AI player.
+	Score() float64
+
+	// AITurn returns true if the next move will be performed by the AI player.
+	AITurn() bool
+
+	// MoveCount returns the number of moves possible in the current state.
+	MoveCount() int
+
+	// Move performs the move at the given index and returns the next state after the move.
+	Move(int) GameState
+
+	// MoveLocation returns the coordinates of the move at the given index.
+	MoveLocation(int) [2]int
+
+	// Corner returns true if the move at the given index takes a corner of the board.
+	Corner(int) bool
+
+	// Key returns a hash of the position and whose turn it is, for the transposition table. The
+	// moves of states with the same key must be in the same order.
+	Key() uint64
+}
+
+// Minimax is the Engine that searches ahead with the minimax algorithm and scores the positions it
+// reaches with the Evaluation of its Limits.
+type Minimax struct{}
+
+// Result describes the move that a search chose and why.
+type Result struct {
+	// Move is the coordinates of the chosen move.
+	Move [2]int
+
+	// Score is how good the move is for the player, as judged by the search.
+	Score float64
+
+	// Depth is the number of turns that the deepest finished search looked ahead.
+	Depth int
+
+	// Nodes is the number of positions that were searched.
+	Nodes int
+
+	// PV is the principal variation, which is the line of play that the search expects, starting
+	// with Move. It may stop short of Depth where the search found the rest of the line in the
+	// transposition table.
+	PV [][2]int
+}
+
+// Endgames with at most this many empty squares are solved exactly when Limits.SolveEndgame is set,
+// instead of being searched with minimax. Boards that do not fit in a bitboard are much slower to
+// solve.
+const (
+	solveEmpties      = 14
+	solveBoardEmpties = 10
+)
+
+func (m Minimax) BestMove(ctx context.Context, board common.Board, player common.Disk, limits Limits) ([2]int, error) {
+	result, err := m.Search(ctx, board, player, limits)
+	return result.Move, err
+}
+
+// Search is like BestMove, but also returns the details of the search, for debugging.
+func (Minimax) Search(ctx context.Context, board common.Board, player common.Disk, limits Limits) (Result, error) {
+	if !common.HasMoves(board, player) {
+		return Result{}, ErrNoMoves
+	}
+
+	players := common.PlayerCount(board)
+
+	if limits.SolveEndgame && players == 2 && board.Empties() <= maxSolveEmpties(board) {
+		start := time.Now()
+
+		solveCtx := ctx
+		if limits.Time > 0 {
+			var cancel context.CancelFunc
+			solveCtx, cancel = context.WithTimeout(ctx, limits.Time)
+			defer cancel()
+		}
+
+		if solution, err := common.Solve(solveCtx, board, player, limits.Variant); err == nil {
+			move := [2]int{solution.X, solution.Y}
+			return Result{
+				Move:  move,
+				Score: float64(solution.Differential),
+				Depth: board.Empties(),
+				PV:    [][2]int{move},
+			}, nil
+		}
+
+		// The solver ran out of time, so search with minimax instead, which will at least finish
+		// its first search.
+		if limits.Time > 0 {
+			limits.Time -= time.Since(start)
+			if limits.Time <= 0 {
+				limits.Time = time.Nanosecond
+			}
+		}
+	}
+
+	// Every move fills a square, so there is no point looking further ahead than the number of
+	// empty squares.
+	if empties := board.Empties(); limits.Depth <= 0 || limits.Depth > empties {
+		limits.Depth = empties
+	}
+
+	state := newGameState(board, player, players, limits.Variant, limits.Evaluation)
+
+	best, nodes, err := iterativeDeepening(ctx, state, limits, newTranspositionTable(defaultTableEntries))
+	if err != nil {
+		return Result{}, err
+	}
+
+	pv := pvLocations(state, best.pv)
+
+	return Result{
+		Move:  pv[0],
+		Score: best.score,
+		Depth: best.depth,
+		Nodes: nodes,
+		PV:    pv,
+	}, nil
+}
+
+// iteration is the result of one search of iterative deepening.
+type iteration struct {
+	move  int
+	score float64
+	depth int
+	pv    []int
+}
+
+// iterativeDeepening searches one turn ahead, then two, and so on up to the depth limit, and
+// returns the result of the deepest search that finished within the time limit, along with the
+// number of positions that were searched. The first search is always allowed to finish, so that
+// there is a move to return, unless the context is done first.
+//
+// The searches share the transposition table, if there is one, so that each search can start with
+// the best moves that the previous search found.
+func iterativeDeepening(ctx context.Context, state GameState, limits Limits, table *transpositionTable) (iteration, int, error) {
+	deadlineCtx := ctx
+	if limits.Time > 0 {
+		var cancel context.CancelFunc
+		deadlineCtx, cancel = context.WithTimeout(ctx, limits.Time)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return iteration{}, 0, err
+	}
+
+	best := iteration{move: -1}
+	nodes := 0
+
+	for depth := 1; depth <= limits.Depth; depth++ {
+		searchCtx := deadlineCtx
+		if best.move < 0 {
+			searchCtx = ctx
+		}
+
+		var (
+			result    iteration
+			iterNodes int
+			ok        bool
+		)
+
+		if limits.Workers > 1 {
+			result, iterNodes, ok = parallelSearchRoot(searchCtx, table, state, depth, best.move, limits.Workers)
+		} else {
+			s := &search{ctx: searchCtx, table: table}
+			result, ok = s.searchRoot(state, depth, best.move)
+			iterNodes = s.nodes
+		}
+
+		nodes += iterNodes
+		if !ok {
+			break
+		}
+
+		best = result
+	}
+
+	if best.move < 0 {
+		return iteration{}, nodes, ctx.Err()
+	}
+
+	return best, nodes, nil
+}
+
+// pvLocations returns the coordinates of the moves of a principal variation that starts at the
+// given state.
+func pvLocations(state GameState, pv []int) [][2]int {
+	locations := make([][2]int, len(pv))
+	for j, i := range pv {
+		locations[j] = state.MoveLocation(i)
+		state = state.Move(i)
+	}
+	return locations
+}
+
+// maxSolveEmpties returns the number of empty squares at or below which the endgame on the board
+// is solved exactly.
+func maxSolveEmpties(board common.Board) int {
+	if _, ok := common.NewPosition(board); ok {
+		return solveEmpties
+	}
+	return solveBoardEmpties
+}
+
+// searchCheckInterval is the number of positions that a search looks at between checks of whether
+// its context is done, since checking the context at every position would slow the search down.
+const searchCheckInterval = 1024
+
+// search is a single negamax search, which stops as soon as its context is done.
+type search struct {
+	ctx     context.Context
+	table   *transpositionTable
+	nodes   int
+	stopped bool
+}
+
+// searchRoot searches the given number of turns ahead of the state, which must be the AI's turn,
+// and returns the best move. The move at index first is searched before the other moves that are
+// not corners, since it is likely to be the best. It returns false if the context was done before
+// the search finished.
+func (s *search) searchRoot(state GameState, depth int, first int) (iteration, bool) {
+	best := iteration{score: math.Inf(-1), depth: depth}
+	alpha := math.Inf(-1)
+
+	for j, m := range s.orderMoves(state, depth, first) {
+		var pv []int
+		score := s.principalVariation(state, m, j == 0, depth-1, alpha, math.Inf(1), &pv)
+		if s.stopped {
+			return iteration{}, false
+		}
+
+		if j == 0 || score > best.score {
+			best.move, best.score, best.pv = m.index, score, append([]int{m.index}, pv...)
+			alpha = math.Max(alpha, score)
+		}
+	}
+
+	return best, true
+}
+
+// negamax is the negamax formulation of the minimax adversarial search algorithm, with alpha-beta
+// pruning. It returns the score of a GameState after searching up to the specified depth n, from
+// the perspective of whoever's turn it is, which is either the AI or the AI's opponents. Once the
+// search is stopped, the score is meaningless.
+//
+// If pv is not nil, it is set to the principal variation of the state when the score is within
+// the window.
+func (s *search) negamax(state GameState, depth int, alpha, beta float64, pv *[]int) float64 {
+	s.nodes++
+	if s.nodes%searchCheckInterval == 0 && s.ctx.Err() != nil {
+		s.stopped = true
+	}
+	if s.stopped {
+		return 0
+	}
+
+	if depth <= 0 || state.MoveCount() <= 0 {
+		if state.AITurn() {
+			return state.Score()
+		}
+		return -state.Score()
+	}
+
+	alphaOrig := alpha
+
+	// A previous search of the position may already have the answer, or at least know which move
+	// to try first.
+	var key uint64
+	first := -1
+	if s.table != nil {
+		key = state.Key()
+		if entry, ok := s.table.load(key); ok {
+			if int(entry.depth) >= depth {
+				switch entry.bound {
+				case boundExact:
+					return entry.score
+				case boundLower:
+					alpha = math.Max(alpha, entry.score)
+				case boundUpper:
+					beta = math.Min(beta, entry.score)
+				}
+				if alpha >= beta {
+					return entry.score
+				}
+			}
+			first = int(entry.move)
+		}
+	}
+
+	best, bestMove := math.Inf(-1), 0
+
+	for j, m := range s.orderMoves(state, depth, first) {
+		var childPV []int
+		var childPVPtr *[]int
+		if pv != nil {
+			childPVPtr = &childPV
+		}
+
+		score := s.principalVariation(state, m, j == 0, depth-1, alpha, beta, childPVPtr)
+		if s.stopped {
+			return 0
+		}
+
+		if score > best {
+			best, bestMove = score, m.index
+		}
+		if score > alpha {
+			alpha = score
+			if pv != nil {
+				*pv = append([]int{m.index}, childPV...)
+			}
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if s.table != nil {
+		entry := ttEntry{score: best, depth: int16(depth), move: int16(bestMove)}
+		switch {
+		case best <= alphaOrig:
+			entry.bound = boundUpper
+		case best >= beta:
+			entry.bound = boundLower
+		default:
+			entry.bound = boundExact
+		}
+		s.table.store(key, entry)
+	}
+
+	return best
+}
+
+// principalVariation returns the score of a move from the perspective of the player who makes it.
+// Principal variation search assumes that the first move is the best, so every other move is
+// searched with a null window, which only proves that the move is no better than alpha. If the
+// proof fails, the move is searched again with the full window to find its true score.
+func (s *search) principalVariation(state GameState, m orderedMove, first bool, depth int, alpha, beta float64, pv *[]int) float64 {
+	if first {
+		return s.childScore(state, m.child, depth, alpha, beta, pv)
+	}
+
+	score := s.childScore(state, m.child, depth, alpha, math.Nextafter(alpha, math.Inf(1)), nil)
+	if score > alpha && score < beta && !s.stopped {
+		score = s.childScore(state, m.child, depth, alpha, beta, pv)
+	}
+
+	return score
+}
+
+// childScore returns the score of the child of a state from the perspective of whoever's turn it
+// is in the state. The score of the child is negated when the turn passes between the AI and its
+// opponents, but not when the same side moves twice, such as after a pass.
+func (s *search) childScore(state, child GameState, depth int, alpha, beta float64, pv *[]int) float64 {
+	if child.AITurn() == state.AITurn() {
+		return s.negamax(child, depth, alpha, beta, pv)
+	}
+	return -s.negamax(child, depth, -beta, -alpha, pv)
+}
+
+// Moves are ordered so that the best moves are likely to be searched first, which lets alpha-beta
+// pruning skip more of the others. Corners are searched first, then the best move that an earlier
+// search found, and then the moves that leave the next player with the fewest replies. Counting
+// the replies is only worth it far enough from the leaves of the search.
+const (
+	cornerPriority        = 2000
+	firstPriority         = 1000
+	mobilityOrderingDepth = 3
+)
+
+// orderedMove is a move of a state along with its child state.
+type orderedMove struct {
+	index    int
+	child    GameState
+	priority int
+}
+
+// orderMoves returns the moves of a state in the order that they should be searched. The move at
+// index first is the best move found by an earlier search, or -1 if there is none.
+func (s *search) orderMoves(state GameState, depth, first int) []orderedMove {
+	moves := make([]orderedMove, state.MoveCount())
+
+	for i := range moves {
+		m := orderedMove{index: i, child: state.Move(i)}
+
+		if state.Corner(i) {
+			m.priority += cornerPriority
+		}
+
+		if i == first {
+			m.priority += firstPriority
+		}
+
+		// When the same side moves again, such as after the opponent passes, the opponent has
+		// no replies at all.
+		if depth >= mobilityOrderingDepth && m.child.AITurn() != state.AITurn() {
+			m.priority -= m.child.MoveCount()
+		}
+
+		moves[i] = m
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].priority > moves[j].priority
+	})
+
+	return moves
+}
@@ -0,0 +1,445 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func BenchmarkMiniMax(b *testing.B) {
+	for _, depth := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			// Enable memory allocation stats for this benchmark test.
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				// New board, and player 1 made the first move.
+				match := common.NewMatch(common.DefaultBoardSize)
+				_ = match.Play(2, 4)
+
+				// Now it's player 2's turn (the AI player).
+				state := newGameState(match.Board, 2, 2, common.Standard, FullEvaluation)
+
+				// Do the thing being benchmarked.
+				s := &search{ctx: context.Background()}
+				s.negamax(state, depth, math.Inf(-1), math.Inf(1), nil)
+			}
+		})
+	}
+}
+
+func TestMinimaxBoardSizes(t *testing.T) {
+	for _, size := range common.BoardSizes {
+		match := common.NewMatch(size)
+		_ = match.Play(size/2-2, size/2)
+
+		move, err := Minimax{}.BestMove(context.Background(), match.Board, 2, Limits{Depth: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := match.Play(move[0], move[1]); err != nil {
+			t.Errorf("BestMove() on a board of size %d got an illegal move: %v", size, err)
+		}
+	}
+}
+
+func TestGameStateAntiOthello(t *testing.T) {
+	// SimpleEvaluation only counts disks, edges and corners, so all of it is negated.
+	for _, size := range []int{common.DefaultBoardSize, 6} {
+		match := common.NewMatch(size)
+		_ = match.Play(size/2-2, size/2)
+
+		standard := newGameState(match.Board, 2, 2, common.Standard, SimpleEvaluation)
+		anti := newGameState(match.Board, 2, 2, common.AntiOthello, SimpleEvaluation)
+
+		if standard.Score() != -anti.Score() {
+			t.Errorf("Score() on a board of size %d got %f for anti-othello, want %f", size, anti.Score(), -standard.Score())
+		}
+
+		if standard.Move(0).Score() != -anti.Move(0).Score() {
+			t.Errorf("Score() after a move on a board of size %d got %f for anti-othello, want %f", size, anti.Move(0).Score(), -standard.Move(0).Score())
+		}
+	}
+
+	// FullEvaluation negates the disk terms, but mobility is as good for the AI in either game,
+	// so the scores differ by twice the mobility terms.
+	match := common.NewMatch(common.DefaultBoardSize)
+	if err := match.PlayTranscript("f5d6c3d3c4"); err != nil {
+		t.Fatal(err)
+	}
+
+	standard := newGameState(match.Board, 2, 2, common.Standard, FullEvaluation).(*bitboardState)
+	anti := newGameState(match.Board, 2, 2, common.AntiOthello, FullEvaluation)
+
+	own, opp := standard.position.Disks(2), standard.position.Disks(1)
+	empty := standard.position.Empty()
+	w := phaseWeights(empty.Count())
+
+	mobility := w.mobility*float64(standard.position.LegalMoves(2).Count()-standard.position.LegalMoves(1).Count()) +
+		w.potentialMobility*float64((empty&opp.Neighbors()).Count()-(empty&own.Neighbors()).Count()) +
+		w.frontier*float64((own&empty.Neighbors()).Count()-(opp&empty.Neighbors()).Count()) +
+		w.parity*float64(standard.parity(empty))
+
+	if mobility == 0 {
+		t.Fatal("the position has no mobility to compare")
+	}
+	if got, want := standard.Score()+anti.Score(), 2*mobility; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Score() for standard and anti-othello add up to %f, want %f", got, want)
+	}
+
+	// The player with the fewest disks wins Anti-Othello.
+	board := common.NewBoard(common.DefaultBoardSize)
+	board.Set(0, 0, common.Player1)
+
+	if got := newGameState(board, common.Player2, 2, common.AntiOthello, FullEvaluation).Score(); got != math.Inf(1) {
+		t.Errorf("Score() of a game of anti-othello won with no disks got %f, want %f", got, math.Inf(1))
+	}
+}
+
+func TestMinimaxMultiplayer(t *testing.T) {
+	for players := 3; players <= common.MaxPlayers; players++ {
+		match := common.NewMatchForPlayers(common.DefaultBoardSize, players)
+
+		for !match.IsOver() && len(match.History) < 12 {
+			move, err := Minimax{}.BestMove(context.Background(), match.Board, match.Player, Limits{Depth: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := match.Play(move[0], move[1]); err != nil {
+				t.Fatalf("BestMove() for player %d of %d got an illegal move: %v", match.Player, players, err)
+			}
+		}
+	}
+}
+
+func TestMinimaxMultiplayerAntiOthello(t *testing.T) {
+	match := common.NewMatchForPlayers(common.DefaultBoardSize, 3)
+	match.SetVariant(common.AntiOthello)
+
+	for !match.IsOver() && len(match.History) < 12 {
+		move, err := Minimax{}.BestMove(context.Background(), match.Board, match.Player, Limits{Depth: 4, Variant: common.AntiOthello})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := match.Play(move[0], move[1]); err != nil {
+			t.Fatalf("BestMove() for player %d got an illegal move: %v", match.Player, err)
+		}
+	}
+
+	// The AI measures itself against the opponent with the fewest disks, not the most.
+	state := &boardState{board: match.Board, players: 3, variant: common.AntiOthello, maximizingPlayer: common.Player1}
+	if got := state.strongestOpponent([]int{3, 5, 1}); got != common.Player3 {
+		t.Errorf("strongestOpponent() of anti-othello got player %d, want player 3", got)
+	}
+	state.variant = common.Standard
+	if got := state.strongestOpponent([]int{3, 5, 1}); got != common.Player2 {
+		t.Errorf("strongestOpponent() of the standard game got player %d, want player 2", got)
+	}
+
+	// Finished games, where no disk is next to another, so nobody can move.
+	for _, tt := range []struct {
+		name       string
+		p1, p2, p3 [][2]int
+		want       float64
+	}{
+		{
+			name: "player 3 has the fewest disks",
+			p1:   [][2]int{{0, 0}, {0, 2}},
+			p2:   [][2]int{{2, 0}, {2, 2}, {4, 0}},
+			p3:   [][2]int{{6, 6}},
+			want: math.Inf(-1),
+		},
+		{
+			name: "player 1 has the fewest disks",
+			p1:   [][2]int{{0, 0}},
+			p2:   [][2]int{{2, 0}, {2, 2}, {4, 0}},
+			p3:   [][2]int{{6, 6}, {6, 4}},
+			want: math.Inf(1),
+		},
+		{
+			name: "players 2 and 3 draw with the fewest disks",
+			p1:   [][2]int{{0, 0}, {0, 2}},
+			p2:   [][2]int{{2, 0}},
+			p3:   [][2]int{{6, 6}},
+			want: math.Inf(-1),
+		},
+		{
+			name: "players 1 and 3 draw with the fewest disks",
+			p1:   [][2]int{{0, 0}},
+			p2:   [][2]int{{2, 0}, {2, 2}},
+			p3:   [][2]int{{6, 6}},
+			want: 0,
+		},
+	} {
+		board := common.NewBoard(common.DefaultBoardSize)
+		for player, squares := range [][][2]int{tt.p1, tt.p2, tt.p3} {
+			for _, square := range squares {
+				board.Set(square[0], square[1], common.Disk(player+1))
+			}
+		}
+
+		state := newGameState(board, common.Player1, 3, common.AntiOthello, FullEvaluation)
+		if got := state.Score(); got != tt.want {
+			t.Errorf("Score() when %s got %f, want %f", tt.name, got, tt.want)
+		}
+	}
+}
+
+// playRandomMoves plays up to n random legal moves in the match, stopping early if the game ends.
+func playRandomMoves(tb testing.TB, rng *rand.Rand, match *common.Match, n int) {
+	tb.Helper()
+
+	for i := 0; i < n && !match.IsOver(); i++ {
+		moves := match.LegalMoves()
+		move := moves[rng.Intn(len(moves))]
+		if err := match.Play(move.X, move.Y); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func TestMinimaxSolvesEndgame(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5; i++ {
+		match := common.NewMatch(common.DefaultBoardSize)
+		playRandomMoves(t, rng, &match, match.Board.Empties()-10)
+		if match.IsOver() {
+			continue
+		}
+
+		want, _ := match.Solve(context.Background())
+		move, err := Minimax{}.BestMove(context.Background(), match.Board, match.Player, Limits{Depth: 6, SolveEndgame: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Another move may be just as good, so compare the outcomes instead of the moves.
+		child, ok := common.ApplyMove(match.Board, move[0], move[1], match.Player)
+		if !ok {
+			t.Fatalf("BestMove() got an illegal move %v", move)
+		}
+		got, _ := common.Solve(context.Background(), child, match.Player%2+1, common.Standard)
+
+		if -got.Differential != want.Differential {
+			t.Errorf("BestMove() got move %v with differential %d, want %d", move, -got.Differential, want.Differential)
+		}
+	}
+}
+
+func TestMinimaxTimeLimit(t *testing.T) {
+	board := common.NewMatch(common.DefaultBoardSize).Board
+
+	start := time.Now()
+	move, err := Minimax{}.BestMove(context.Background(), board, common.Player1, Limits{Time: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("BestMove() with a time limit of 50ms took %v", elapsed)
+	}
+
+	if _, ok := common.ApplyMove(board, move[0], move[1], common.Player1); !ok {
+		t.Errorf("BestMove() got an illegal move %v", move)
+	}
+}
+
+func TestMinimaxSolveTimeLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// A large board takes much longer than the time limit to solve.
+	match := common.NewMatch(10)
+	for {
+		playRandomMoves(t, rng, &match, match.Board.Empties()-10)
+		if !match.IsOver() {
+			break
+		}
+		match = common.NewMatch(10)
+	}
+
+	result, err := Minimax{}.Search(context.Background(), match.Board, match.Player, Limits{Time: time.Millisecond, SolveEndgame: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Depth >= match.Board.Empties() {
+		t.Errorf("Search() of an endgame with a time limit of 1ms got depth %d, want the solver to give up", result.Depth)
+	}
+
+	if _, ok := common.ApplyMove(match.Board, result.Move[0], result.Move[1], match.Player); !ok {
+		t.Errorf("Search() got an illegal move %v", result.Move)
+	}
+}
+
+func TestIterativeDeepeningDepthLimit(t *testing.T) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	_ = match.Play(2, 4)
+
+	// The same search without iterative deepening.
+	state := newGameState(match.Board, 2, 2, common.Standard, FullEvaluation)
+	want, _ := (&search{ctx: context.Background()}).searchRoot(state, 4, -1)
+
+	got, _, err := iterativeDeepening(context.Background(), newGameState(match.Board, 2, 2, common.Standard, FullEvaluation), Limits{Depth: 4}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The moves are searched in a different order, so another move with the same score may win.
+	if got.depth != 4 || got.score != want.score {
+		t.Errorf("iterativeDeepening() got depth %d and score %f, want depth 4 and score %f", got.depth, got.score, want.score)
+	}
+}
+
+func TestMinimaxNoMoves(t *testing.T) {
+	board := common.NewBoard(common.DefaultBoardSize)
+	board.Set(0, 0, common.Player1)
+
+	if _, err := (Minimax{}).BestMove(context.Background(), board, common.Player1, Limits{Depth: 4}); !errors.Is(err, ErrNoMoves) {
+		t.Errorf("BestMove() got error %v, want %v", err, ErrNoMoves)
+	}
+}
+
+func TestMinimaxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	board := common.NewMatch(common.DefaultBoardSize).Board
+
+	if _, err := (Minimax{}).BestMove(ctx, board, common.Player1, Limits{Depth: 4}); !errors.Is(err, context.Canceled) {
+		t.Errorf("BestMove() got error %v, want %v", err, context.Canceled)
+	}
+}
+
+// plainMinimax is minimax without pruning, move ordering or a transposition table, to check the
+// faster search against.
+func plainMinimax(state GameState, depth int) float64 {
+	if depth <= 0 || state.MoveCount() <= 0 {
+		return state.Score()
+	}
+
+	best := plainMinimax(state.Move(0), depth-1)
+	for i := 1; i < state.MoveCount(); i++ {
+		score := plainMinimax(state.Move(i), depth-1)
+		if state.AITurn() == (score > best) {
+			best = score
+		}
+	}
+
+	return best
+}
+
+func TestNegamaxMatchesMinimax(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, tt := range []struct{ size, players int }{{8, 2}, {6, 2}, {8, 3}} {
+		for i := 0; i < 5; i++ {
+			match := common.NewMatchForPlayers(tt.size, tt.players)
+			playRandomMoves(t, rng, &match, 8+i)
+			if match.IsOver() {
+				continue
+			}
+
+			want := plainMinimax(newGameState(match.Board, match.Player, tt.players, common.Standard, FullEvaluation), 4)
+
+			for _, table := range []*transpositionTable{nil, newTranspositionTable(1 << 12)} {
+				s := &search{ctx: context.Background(), table: table}
+				got, _ := s.searchRoot(newGameState(match.Board, match.Player, tt.players, common.Standard, FullEvaluation), 4, -1)
+
+				if got.score != want {
+					t.Errorf("searchRoot() on a board of size %d with %d players got score %f, want %f\n%s", tt.size, tt.players, got.score, want, match.Board)
+				}
+			}
+		}
+	}
+}
+
+func TestMinimaxSearch(t *testing.T) {
+	match := common.NewMatch(common.DefaultBoardSize)
+
+	result, err := Minimax{}.Search(context.Background(), match.Board, common.Player1, Limits{Depth: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Depth != 5 || result.Nodes <= 0 {
+		t.Errorf("Search() got depth %d and %d nodes", result.Depth, result.Nodes)
+	}
+
+	if len(result.PV) == 0 || result.PV[0] != result.Move {
+		t.Fatalf("Search() got principal variation %v for move %v", result.PV, result.Move)
+	}
+
+	for _, move := range result.PV {
+		if err := match.Play(move[0], move[1]); err != nil {
+			t.Errorf("Search() got an illegal principal variation %v: %v", result.PV, err)
+			break
+		}
+	}
+}
+
+func TestBitboardStateMatchesBoardState(t *testing.T) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	_ = match.Play(2, 4)
+	_ = match.Play(2, 3)
+
+	for _, variant := range common.Variants {
+		for _, player := range []common.Disk{common.Player1, common.Player2} {
+			bitboard := newGameState(match.Board, player, 2, variant, SimpleEvaluation)
+			board := &boardState{board: match.Board, players: 2, variant: variant, maximizingPlayer: player, turn: player}
+
+			if got, want := bitboard.MoveCount(), board.MoveCount(); got != want {
+				t.Fatalf("MoveCount() for player %d got %d, want %d", player, got, want)
+			}
+
+			// The states enumerate their moves in different orders, so pair them up by their squares.
+			boardMoves := make(map[[2]int]int)
+			for i := 0; i < board.MoveCount(); i++ {
+				boardMoves[board.MoveLocation(i)] = i
+			}
+
+			for i := 0; i < bitboard.MoveCount(); i++ {
+				location := bitboard.MoveLocation(i)
+				j, ok := boardMoves[location]
+				if !ok {
+					t.Errorf("Move %v for player %d is missing from the board state", location, player)
+					continue
+				}
+
+				if got, want := bitboard.Move(i).Score(), board.Move(j).Score(); got != want {
+					t.Errorf("Score() of %s for player %d after move %v got %f, want %f", variant, player, location, got, want)
+				}
+			}
+		}
+	}
+
+	// The game is over and player 1 has the only disk, so player 2 has lost the standard game and
+	// won Anti-Othello.
+	board := common.NewBoard(common.DefaultBoardSize)
+	board.Set(0, 0, common.Player1)
+
+	for _, tt := range []struct {
+		variant common.Variant
+		want    float64
+	}{
+		{common.Standard, math.Inf(-1)},
+		{common.AntiOthello, math.Inf(1)},
+	} {
+		if got := newGameState(board, common.Player2, 2, tt.variant, FullEvaluation).Score(); got != tt.want {
+			t.Errorf("Score() of a finished game of %s got %f, want %f", tt.variant, got, tt.want)
+		}
+		state := &boardState{board: board, players: 2, variant: tt.variant, maximizingPlayer: common.Player2, turn: common.Player2}
+		if got := state.Score(); got != tt.want {
+			t.Errorf("Score() of a finished game of %s on the board state got %f, want %f", tt.variant, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// parallelSearchRoot is searchRoot split across several goroutines. The first move is searched
+// alone, to find a score for the others to beat. Then the other moves are shared out among the
+// workers, which each search one move at a time with a null window against the best score found
+// so far, and search again with a full window only when a move turns out to be better. The workers
+// share the transposition table, so that they benefit from each other's work.
+//
+// Which move is chosen when two moves have the same score depends on the order that the workers
+// finish in, so unlike searchRoot, the result is not deterministic. It also returns the number of
+// positions that were searched.
+func parallelSearchRoot(ctx context.Context, table *transpositionTable, state GameState, depth, first, workers int) (iteration, int, bool) {
+	leader := &search{ctx: ctx, table: table}
+	moves := leader.orderMoves(state, depth, first)
+
+	var pv []int
+	score := leader.principalVariation(state, moves[0], true, depth-1, math.Inf(-1), math.Inf(1), &pv)
+	if leader.stopped {
+		return iteration{}, leader.nodes, false
+	}
+
+	var (
+		mu      sync.Mutex
+		best    = iteration{move: moves[0].index, score: score, depth: depth, pv: append([]int{moves[0].index}, pv...)}
+		nodes   = leader.nodes
+		stopped bool
+		wg      sync.WaitGroup
+	)
+
+	jobs := make(chan orderedMove, len(moves)-1)
+	for _, m := range moves[1:] {
+		jobs <- m
+	}
+	close(jobs)
+
+	if workers > len(moves)-1 {
+		workers = len(moves) - 1
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s := &search{ctx: ctx, table: table}
+			defer func() {
+				mu.Lock()
+				nodes += s.nodes
+				stopped = stopped || s.stopped
+				mu.Unlock()
+			}()
+
+			for m := range jobs {
+				mu.Lock()
+				alpha := best.score
+				mu.Unlock()
+
+				var pv []int
+				score := s.principalVariation(state, m, false, depth-1, alpha, math.Inf(1), &pv)
+				if s.stopped {
+					return
+				}
+
+				mu.Lock()
+				if score > best.score {
+					best.move, best.score, best.pv = m.index, score, append([]int{m.index}, pv...)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return best, nodes, !stopped
+}
@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestParallelSearchRoot(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5; i++ {
+		match := common.NewMatch(common.DefaultBoardSize)
+		playRandomMoves(t, rng, &match, 10+2*i)
+		if match.IsOver() {
+			continue
+		}
+
+		state := newGameState(match.Board, match.Player, 2, common.Standard, FullEvaluation)
+		want, _ := (&search{ctx: context.Background()}).searchRoot(state, 5, -1)
+
+		state = newGameState(match.Board, match.Player, 2, common.Standard, FullEvaluation)
+		got, nodes, ok := parallelSearchRoot(context.Background(), newTranspositionTable(1<<12), state, 5, -1, 4)
+
+		// The workers may choose a different move with the same score.
+		if !ok || got.score != want.score {
+			t.Errorf("parallelSearchRoot() got score %f, %v, want %f\n%s", got.score, ok, want.score, match.Board)
+		}
+		if nodes <= 0 || len(got.pv) == 0 || got.pv[0] != got.move {
+			t.Errorf("parallelSearchRoot() got %d nodes and principal variation %v for move %d", nodes, got.pv, got.move)
+		}
+	}
+}
+
+func TestMinimaxWorkers(t *testing.T) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	_ = match.PlayTranscript("f5d6c3d3c4f4")
+
+	for _, workers := range []int{0, 1, 4} {
+		move, err := Minimax{}.BestMove(context.Background(), match.Board, match.Player, Limits{Depth: 5, Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := common.ApplyMove(match.Board, move[0], move[1], match.Player); !ok {
+			t.Errorf("BestMove() with %d workers got an illegal move %v", workers, move)
+		}
+	}
+}
+
+func TestMinimaxWorkersTimeLimit(t *testing.T) {
+	board := common.NewMatch(common.DefaultBoardSize).Board
+
+	start := time.Now()
+	move, err := Minimax{}.BestMove(context.Background(), board, common.Player1, Limits{Time: 50 * time.Millisecond, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("BestMove() with a time limit of 50ms took %v", elapsed)
+	}
+
+	if _, ok := common.ApplyMove(board, move[0], move[1], common.Player1); !ok {
+		t.Errorf("BestMove() got an illegal move %v", move)
+	}
+}
+
+// BenchmarkParallelSearch compares the time to search the same position to depth 8 with different
+// numbers of workers.
+func BenchmarkParallelSearch(b *testing.B) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	_ = match.PlayTranscript("f5d6c3d3c4f4")
+
+	for _, workers := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = Minimax{}.BestMove(context.Background(), match.Board, match.Player, Limits{Depth: 8, Workers: workers})
+			}
+		})
+	}
+}
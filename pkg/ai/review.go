@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// Mistake is how bad a move was, compared to the best move in its position.
+type Mistake string
+
+const (
+	// NoMistake is a move that was close enough to the best move.
+	NoMistake = Mistake("")
+
+	// Inaccuracy is a move that gave away some of the player's advantage.
+	Inaccuracy = Mistake("inaccuracy")
+
+	// Blunder is a move that gave away a lot of the player's advantage, such as a corner.
+	Blunder = Mistake("blunder")
+)
+
+// The smallest swings in score that are an Inaccuracy and a Blunder. A corner is worth about 25
+// early in the game and 8 at the end, so giving one away is always at least an inaccuracy.
+const (
+	inaccuracySwing = 5
+	blunderSwing    = 15
+)
+
+// MoveReview is the analysis of a move that was played in a game.
+type MoveReview struct {
+	// Turn is the move that was played.
+	Turn common.Turn
+
+	// Score is how good the move was for the player, as judged by Analyze.
+	Score float64
+
+	// Best is the move that Analyze recommends instead, and BestScore is its score. It may be the
+	// move that was played.
+	Best      [2]int
+	BestScore float64
+
+	// Swing is how much worse the move was than the best move, which is never negative.
+	Swing float64
+
+	Mistake Mistake
+}
+
+// Review replays the moves of a match from its start, and analyzes the position before each move
+// with the given limits, to find out how much each move changed the player's prospects. Passes are
+// skipped, since there is no choice to review.
+//
+// If the context has a deadline, the time that is left is shared evenly between the positions
+// that are left, so that each one gets no more than its share. If the context is done before every
+// move is reviewed, Review returns the reviews of the moves before it.
+func Review(ctx context.Context, match common.Match, limits Limits) ([]MoveReview, error) {
+	replay := match.Restart()
+	limits.Variant = match.Variant
+
+	moves := 0
+	for _, turn := range match.History {
+		if !turn.Pass {
+			moves++
+		}
+	}
+
+	var reviews []MoveReview
+
+	for _, turn := range match.History {
+		if turn.Pass {
+			continue
+		}
+
+		positionLimits := limits
+		if deadline, ok := ctx.Deadline(); ok {
+			share := time.Until(deadline) / time.Duration(moves-len(reviews))
+			if share <= 0 {
+				break
+			}
+			if positionLimits.Time <= 0 || share < positionLimits.Time {
+				positionLimits.Time = share
+			}
+		}
+
+		scores, err := Minimax{}.Analyze(ctx, replay.Board, replay.Player, positionLimits)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return nil, err
+		}
+
+		review := MoveReview{Turn: turn, Best: scores[0].Move, BestScore: scores[0].Score, Score: scores[0].Score}
+		for _, score := range scores {
+			if score.Move == [2]int{turn.X, turn.Y} {
+				review.Score = score.Score
+			}
+		}
+
+		// Scores are infinite when the game is won or lost, and the difference between two equal
+		// infinities is not a number.
+		if review.Score != review.BestScore {
+			review.Swing = review.BestScore - review.Score
+		}
+
+		switch {
+		case review.Swing >= blunderSwing:
+			review.Mistake = Blunder
+		case review.Swing >= inaccuracySwing:
+			review.Mistake = Inaccuracy
+		}
+
+		reviews = append(reviews, review)
+
+		if err := replay.Play(turn.X, turn.Y); err != nil {
+			return nil, fmt.Errorf("replaying move (%d, %d) of player %d: %w", turn.X, turn.Y, turn.Player, err)
+		}
+	}
+
+	if reviews == nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return reviews, nil
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestReviewBestMoves(t *testing.T) {
+	limits := Limits{Depth: 2}
+
+	// A game where every move is the one that Analyze recommends.
+	match := common.NewMatch(common.DefaultBoardSize)
+	for !match.IsOver() && len(match.History) < 12 {
+		scores, err := Minimax{}.Analyze(context.Background(), match.Board, match.Player, limits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = match.Play(scores[0].Move[0], scores[0].Move[1])
+	}
+
+	reviews, err := Review(context.Background(), match, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reviews) != len(match.History) {
+		t.Fatalf("Review() got %d moves, want %d", len(reviews), len(match.History))
+	}
+
+	for i, review := range reviews {
+		if review.Swing != 0 || review.Mistake != NoMistake {
+			t.Errorf("Review() of move %d got swing %f and mistake %q, want none", i, review.Swing, review.Mistake)
+		}
+	}
+}
+
+func TestReviewRandomMoves(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	match := common.NewMatch(6)
+	playRandomMoves(t, rng, &match, match.Board.Empties())
+
+	reviews, err := Review(context.Background(), match, Limits{Depth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mistakes := 0
+	for i, review := range reviews {
+		if review.Swing < 0 || review.Score > review.BestScore {
+			t.Errorf("Review() of move %d got score %f, swing %f and best score %f", i, review.Score, review.Swing, review.BestScore)
+		}
+		if review.Mistake != NoMistake {
+			mistakes++
+		}
+	}
+
+	if mistakes == 0 {
+		t.Errorf("Review() of a game of random moves found no mistakes")
+	}
+}
+
+func TestReviewWithoutStart(t *testing.T) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	if err := match.PlayTranscript("f5d6c3d3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A match that was saved before it recorded its starting position.
+	match.Start, match.StartPlayer = common.Board{}, 0
+
+	reviews, err := Review(context.Background(), match, Limits{Depth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 4 {
+		t.Errorf("Review() got %d moves, want 4", len(reviews))
+	}
+}
+
+func TestReviewDeadline(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	match := common.NewMatch(common.DefaultBoardSize)
+	playRandomMoves(t, rng, &match, match.Board.Empties())
+
+	// Each position would take 100ms, so the whole game takes much longer than the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	reviews, err := Review(ctx, match, Limits{Time: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Review() with a deadline of 200ms took %v", elapsed)
+	}
+	if len(reviews) == 0 {
+		t.Error("Review() with a deadline got no moves")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Review(ctx, match, Limits{Depth: 2}); err != context.Canceled {
+		t.Errorf("Review() with a canceled context got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,161 @@
+package ai
+
+import (
+	"math"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// newGameState returns the fastest GameState that supports the board. Two-player games on
+// boards of the default size use bitboards, and all other games fall back to a slower
+// implementation, which always uses SimpleEvaluation.
+func newGameState(board common.Board, player common.Disk, players int, variant common.Variant, evaluation Evaluation) GameState {
+	if position, ok := common.NewPosition(board); ok && players == 2 {
+		return &bitboardState{
+			position:         position,
+			variant:          variant,
+			evaluation:       evaluation,
+			maximizingPlayer: player,
+			turn:             player,
+		}
+	}
+
+	return &boardState{
+		board:            board,
+		players:          players,
+		variant:          variant,
+		maximizingPlayer: player,
+		turn:             player,
+	}
+}
+
+// Masks used by the AI to value squares.
+const (
+	cornerSquares common.Bitboard = 0x8100000000000081
+	edgeSquares   common.Bitboard = 0x7e8181818181817e
+)
+
+// bitboardState implements the othelgo domain-specific logic needed by the AI. It uses the bitboard
+// representation of the board, since the AI looks at a very large number of positions.
+type bitboardState struct {
+	position         common.Position
+	variant          common.Variant
+	evaluation       Evaluation
+	turn             common.Disk
+	maximizingPlayer common.Disk
+	moves            []common.Position
+	moveLocations    [][2]int
+}
+
+func (a *bitboardState) Score() float64 {
+	p1, p2 := a.position.Score()
+
+	if a.position.GameOver() {
+		switch a.variant.Winner([]int{p1, p2}) {
+		case a.maximizingPlayer:
+			return math.Inf(1)
+		case 0:
+			return 0
+		default:
+			return math.Inf(-1)
+		}
+	}
+
+	if a.maximizingPlayer == 1 {
+		p1, p2 = p2, p1
+	}
+
+	if a.evaluation != SimpleEvaluation {
+		return a.fullScore()
+	}
+
+	trueScoreDelta := float64(p2 - p1)
+	scoreModifier := a.scoreModifier(2) - a.scoreModifier(1)
+
+	// Modifier strength decreases as the board fills up.
+	scoreModifier *= a.percentFull()
+
+	return diskSign(a.variant) * (trueScoreDelta + scoreModifier)
+}
+
+// diskSign returns the sign of the terms of a score that count disks, including the edges and
+// corners that the players hold. In Anti-Othello the AI wants the fewest disks, so holding them is
+// bad for it, but having moves is as good for it as in the standard game.
+func diskSign(variant common.Variant) float64 {
+	if variant == common.AntiOthello {
+		return -1
+	}
+	return 1
+}
+
+func (a *bitboardState) scoreModifier(player common.Disk) (score float64) {
+	disks := a.position.Disks(player)
+
+	// Edges are valuable.
+	score += 0.5 * float64((disks & edgeSquares).Count())
+
+	// Corners are highly valuable.
+	score += 2 * float64((disks & cornerSquares).Count())
+
+	return score
+}
+
+func (a *bitboardState) percentFull() float64 {
+	freeCells := a.position.Empty().Count()
+	return float64(freeCells) / common.DefaultBoardSize / common.DefaultBoardSize
+}
+
+func (a *bitboardState) Key() uint64 {
+	return a.position.Key(a.turn)
+}
+
+func (a *bitboardState) AITurn() bool {
+	return a.turn == a.maximizingPlayer
+}
+
+func (a *bitboardState) MoveCount() int {
+	if a.moves == nil {
+		legalMoves := a.position.LegalMoves(a.turn)
+		a.moves = make([]common.Position, 0, legalMoves.Count())
+		a.moveLocations = make([][2]int, 0, legalMoves.Count())
+
+		for _, square := range legalMoves.Squares() {
+			position, _ := a.position.ApplyMove(square[0], square[1], a.turn)
+			a.moves = append(a.moves, position)
+			a.moveLocations = append(a.moveLocations, square)
+		}
+	}
+
+	return len(a.moves)
+}
+
+func (a *bitboardState) MoveLocation(i int) [2]int {
+	a.MoveCount() // Lazy initialize moves
+
+	return a.moveLocations[i]
+}
+
+func (a *bitboardState) Corner(i int) bool {
+	a.MoveCount() // Lazy initialize moves
+
+	x, y := a.moveLocations[i][0], a.moveLocations[i][1]
+	return x%(common.DefaultBoardSize-1) == 0 && y%(common.DefaultBoardSize-1) == 0
+}
+
+func (a *bitboardState) Move(i int) GameState {
+	a.MoveCount() // Lazy initialize moves
+
+	nextState := &bitboardState{
+		position:         a.moves[i],
+		variant:          a.variant,
+		evaluation:       a.evaluation,
+		turn:             a.turn,
+		maximizingPlayer: a.maximizingPlayer,
+	}
+
+	if a.moves[i].HasMoves(a.turn%2 + 1) {
+		nextState.turn = a.turn%2 + 1
+	}
+
+	return nextState
+}
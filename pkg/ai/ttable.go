@@ -0,0 +1,86 @@
+package ai
+
+import "sync"
+
+// A transposition table remembers the results of searching positions, so that a position which is
+// reached by more than one order of moves is only searched once, and so that each iteration of
+// iterative deepening can try the best move of the previous iteration first.
+
+// bound says how the score of a table entry relates to the true score of its position, since a
+// search that is cut off by alpha-beta pruning only finds a bound on the score.
+type bound uint8
+
+const (
+	boundExact bound = iota
+	boundLower
+	boundUpper
+)
+
+// ttEntry is a transposition table entry.
+type ttEntry struct {
+	key   uint64
+	score float64
+	depth int16
+	move  int16
+	bound bound
+	used  bool
+}
+
+// ttStripes is the number of locks that guard a table. Each lock guards the entries whose index is
+// congruent to it modulo ttStripes, so that searches running in parallel rarely wait for each
+// other.
+const ttStripes = 64
+
+// defaultTableEntries is the number of entries in the table of a search, which is a few megabytes.
+const defaultTableEntries = 1 << 17
+
+// transpositionTable is a fixed-size, concurrency-safe table of search results, keyed by the hash
+// of a position and the player whose turn it is. When two positions need the same slot, the one
+// that was searched deeper is kept, and the newer one wins a tie.
+type transpositionTable struct {
+	entries []ttEntry
+	mask    uint64
+	locks   [ttStripes]sync.Mutex
+}
+
+// newTranspositionTable returns a table with room for the given number of entries, rounded down
+// to a power of two.
+func newTranspositionTable(entries int) *transpositionTable {
+	size := 1
+	for size*2 <= entries {
+		size *= 2
+	}
+
+	return &transpositionTable{
+		entries: make([]ttEntry, size),
+		mask:    uint64(size - 1),
+	}
+}
+
+// load returns the entry of the position with the given key, if it is in the table.
+func (t *transpositionTable) load(key uint64) (ttEntry, bool) {
+	i := key & t.mask
+	lock := &t.locks[i%ttStripes]
+
+	lock.Lock()
+	entry := t.entries[i]
+	lock.Unlock()
+
+	return entry, entry.used && entry.key == key
+}
+
+// store saves the result of searching the position with the given key, unless a deeper search
+// of another position is already in its slot.
+func (t *transpositionTable) store(key uint64, entry ttEntry) {
+	entry.key = key
+	entry.used = true
+
+	i := key & t.mask
+	lock := &t.locks[i%ttStripes]
+
+	lock.Lock()
+	if old := t.entries[i]; !old.used || old.key == key || old.depth <= entry.depth {
+		t.entries[i] = entry
+	}
+	lock.Unlock()
+}
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestTranspositionTable(t *testing.T) {
+	table := newTranspositionTable(1000)
+
+	if got := len(table.entries); got != 512 {
+		t.Errorf("newTranspositionTable(1000) got %d entries, want 512", got)
+	}
+
+	if _, ok := table.load(42); ok {
+		t.Errorf("load() from an empty table got ok = true")
+	}
+
+	table.store(42, ttEntry{score: 1.5, depth: 3, move: 2, bound: boundLower})
+
+	entry, ok := table.load(42)
+	if !ok || entry.score != 1.5 || entry.depth != 3 || entry.move != 2 || entry.bound != boundLower {
+		t.Errorf("load() got %+v, %v", entry, ok)
+	}
+
+	// Another position in the same slot does not replace a deeper one.
+	table.store(42+512, ttEntry{depth: 2})
+	if _, ok := table.load(42 + 512); ok {
+		t.Errorf("store() replaced a deeper entry with a shallower one")
+	}
+
+	table.store(42+512, ttEntry{depth: 3})
+	if _, ok := table.load(42 + 512); !ok {
+		t.Errorf("store() did not replace an entry with one of the same depth")
+	}
+	if _, ok := table.load(42); ok {
+		t.Errorf("load() of a replaced entry got ok = true")
+	}
+}
+
+func TestTranspositionTableConcurrent(t *testing.T) {
+	table := newTranspositionTable(256)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 10000; i++ {
+				key := uint64(g*10000 + i)
+				table.store(key, ttEntry{score: float64(key), depth: int16(i % 8)})
+
+				if entry, ok := table.load(key ^ 1); ok && entry.score != float64(key^1) {
+					t.Errorf("load() got a torn entry %+v for key %d", entry, key^1)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestTranspositionTableSameResult(t *testing.T) {
+	// The table must not change which move is chosen at a fixed depth.
+	for _, size := range []int{common.DefaultBoardSize, 6} {
+		match := common.NewMatch(size)
+		_ = match.Play(size/2-2, size/2)
+		_ = match.Play(size/2-2, size/2-2)
+
+		want, _, _ := iterativeDeepening(context.Background(), newGameState(match.Board, 1, 2, common.Standard, FullEvaluation), Limits{Depth: 5}, nil)
+		got, _, _ := iterativeDeepening(context.Background(), newGameState(match.Board, 1, 2, common.Standard, FullEvaluation), Limits{Depth: 5}, newTranspositionTable(defaultTableEntries))
+
+		if got.move != want.move || got.score != want.score {
+			t.Errorf("iterativeDeepening() on a board of size %d with a table got move %d with score %f, want move %d with score %f", size, got.move, got.score, want.move, want.score)
+		}
+	}
+}
+
+// BenchmarkTranspositionTable compares the number of positions searched to depth 6 with and
+// without a transposition table.
+func BenchmarkTranspositionTable(b *testing.B) {
+	match := common.NewMatch(common.DefaultBoardSize)
+	_ = match.Play(2, 4)
+
+	for _, useTable := range []bool{false, true} {
+		b.Run(fmt.Sprintf("table=%v", useTable), func(b *testing.B) {
+			b.ReportAllocs()
+
+			var nodes int
+			for i := 0; i < b.N; i++ {
+				var table *transpositionTable
+				if useTable {
+					table = newTranspositionTable(defaultTableEntries)
+				}
+
+				state := newGameState(match.Board, 2, 2, common.Standard, FullEvaluation)
+				_, n, _ := iterativeDeepening(context.Background(), state, Limits{Depth: 6}, table)
+				nodes += n
+			}
+
+			b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+		})
+	}
+}
@@ -163,6 +163,21 @@ func Green() (fg, bg termbox.Attribute) {
 	return termbox.ColorGreen, termbox.ColorDefault
 }
 
+// Cyan is a cyan Color.
+func Cyan() (fg, bg termbox.Attribute) {
+	return termbox.ColorCyan, termbox.ColorDefault
+}
+
+// Yellow is a yellow Color.
+func Yellow() (fg, bg termbox.Attribute) {
+	return termbox.ColorYellow, termbox.ColorDefault
+}
+
+// Red is a red Color.
+func Red() (fg, bg termbox.Attribute) {
+	return termbox.ColorRed, termbox.ColorDefault
+}
+
 func Border(decoration string) {
 	if decoration == "" {
 		return
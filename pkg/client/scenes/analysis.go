@@ -0,0 +1,88 @@
+package scenes
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/nsf/termbox-go"
+
+	"github.com/armsnyder/othelgo/pkg/client/draw"
+	"github.com/armsnyder/othelgo/pkg/common"
+	"github.com/armsnyder/othelgo/pkg/messages"
+)
+
+// analysisRows is the number of moves that fit on the screen at once.
+const analysisRows = 15
+
+// Analysis shows the review of every move of a finished game as a table, which scrolls when the
+// game has more moves than fit on the screen.
+type Analysis struct {
+	scene
+	game   *Game
+	moves  []messages.MoveAnalysis
+	scroll int
+}
+
+func (a *Analysis) OnTerminalEvent(event termbox.Event) error {
+	if unicode.ToUpper(event.Ch) == 'M' {
+		a.OnQuit()
+		g := a.game
+		return a.ChangeScene(&Menu{nickname: g.nickname, boardSize: g.boardSize, variant: g.variant, handicap: g.handicap, obstacles: g.obstacles, players: g.players, color: g.color, allowHints: g.allowHints})
+	}
+
+	_, dy := getDirectionPressed(event)
+	a.scroll = clamp(a.scroll+dy, 0, max(len(a.moves)-analysisRows+1, 1))
+
+	return nil
+}
+
+// OnQuit leaves the game, which was kept open until now so that it could be analyzed.
+func (a *Analysis) OnQuit() {
+	a.game.OnQuit()
+}
+
+func (a *Analysis) Draw() {
+	draw.Draw(draw.TopLeft, draw.Normal, "GAME ANALYSIS")
+	draw.Draw(draw.TopRight, draw.Normal, fmt.Sprintf("Your name is %s!", strings.ToUpper(a.game.nickname)))
+	draw.Draw(draw.BotRight, draw.Normal, "[W/S] SCROLL  [M] MENU  [Q] QUIT")
+
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 2), draw.Normal, fmt.Sprintf("%4s  %-8s  %-6s  %-6s  %8s", "MOVE", "PLAYER", "PLAYED", "BEST", "SWING"))
+
+	for row := 0; row < analysisRows && a.scroll+row < len(a.moves); row++ {
+		i := a.scroll + row
+		move := a.moves[i]
+		y := 3 + row
+
+		name := a.game.opponent
+		if move.Player == a.game.player {
+			name = a.game.nickname
+		}
+
+		size := a.game.size()
+		line := fmt.Sprintf("%4d  %-8.8s  %-6s  %-6s  %8s", i+1, strings.ToUpper(name),
+			common.FormatSquare(size, move.X, move.Y), common.FormatSquare(size, move.BestX, move.BestY), formatSwing(move.Swing))
+
+		color := draw.Normal
+		switch move.Mistake {
+		case "blunder":
+			color = draw.Red
+		case "inaccuracy":
+			color = draw.Yellow
+		}
+
+		draw.Draw(draw.Offset(draw.TopLeft, 0, y), color, line)
+		if move.Mistake != "" {
+			draw.Draw(draw.Offset(draw.TopLeft, len(line)+2, y), color, strings.ToUpper(move.Mistake))
+		}
+	}
+}
+
+// formatSwing formats the swing of a move, which is infinite when the move threw away a won game.
+func formatSwing(swing float64) string {
+	if swing >= math.MaxFloat64 {
+		return "∞"
+	}
+	return fmt.Sprintf("%.1f", swing)
+}
@@ -19,19 +19,26 @@ type Game struct {
 	player       common.Disk
 	curSquareX   int
 	curSquareY   int
-	board        common.Board
-	p1Score      int
-	p2Score      int
+	match        common.Match
+	scores       []int
 	confetti     confetti
 	nickname     string
 	host         string
 	opponent     string
-	whoseTurn    common.Disk
 	multiplayer  bool
 	difficulty   int
+	boardSize    int
+	variant      common.Variant
+	handicap     common.Handicap
+	obstacles    int
+	players      int
+	color        messages.Color
+	allowHints   bool
 	alertMessage string
 	prevX        int
 	prevY        int
+	hintX        int
+	hintY        int
 }
 
 func (g *Game) Setup(changeScene ChangeScene, sendMessage SendMessage) error {
@@ -43,31 +50,61 @@ func (g *Game) Setup(changeScene ChangeScene, sendMessage SendMessage) error {
 		g.alertMessage = "Waiting for opponent"
 	}
 
+	// There is no previous move to highlight until the first move is made.
+	g.prevX, g.prevY = -1, -1
+	g.hintX, g.hintY = -1, -1
+
 	var message interface{}
 	if g.multiplayer {
 		if g.player == 1 {
-			message = messages.HostGame{Nickname: g.nickname}
+			message = messages.HostGame{Nickname: g.nickname, AllowHints: g.allowHints, GameSettings: g.settings()}
 		} else {
-			message = messages.JoinGame{Nickname: g.nickname, Host: g.host}
+			message = messages.JoinGame{Nickname: g.nickname, Host: g.host, AllowHints: g.allowHints}
 		}
 	} else {
-		message = messages.StartSoloGame{Nickname: g.nickname, Difficulty: g.difficulty}
+		message = messages.StartSoloGame{Nickname: g.nickname, Difficulty: g.difficulty, Color: g.color, GameSettings: g.settings()}
 	}
 
 	return sendMessage(message)
 }
 
+// settings returns the settings of the new game that was chosen in the menu. The handicap is
+// given to the host or the human player. Only solo games can have more than two players.
+func (g *Game) settings() messages.GameSettings {
+	settings := messages.GameSettings{
+		BoardSize: g.boardSize,
+		Variant:   g.variant,
+		Handicap:  g.handicap,
+		Obstacles: g.obstacles,
+	}
+	if !g.multiplayer {
+		settings.Players = g.players
+	}
+	return settings
+}
+
 func (g *Game) OnMessage(message interface{}) error {
 	switch m := message.(type) {
 	case *messages.UpdateBoard:
-		g.board = m.Board
-		g.whoseTurn = m.Player
-		g.p1Score = m.P1Score
-		g.p2Score = m.P2Score
+		g.match = common.NewMatchFromPosition(m.Board, m.Player)
+		g.match.SetVariant(m.Variant)
+		g.variant = m.Variant
+		g.scores = m.Scores
+		// In a solo game, the human may not be player 1, such as when they chose a random color.
+		if !g.multiplayer && m.HumanPlayer != 0 {
+			g.player = m.HumanPlayer
+		}
 		if m.X >= 0 && m.Y >= 0 {
 			g.prevX = m.X
 			g.prevY = m.Y
 		}
+		// A hint is only good until the board changes.
+		g.hintX, g.hintY = -1, -1
+	case *messages.Analysis:
+		g.hintX = m.X
+		g.hintY = m.Y
+	case *messages.GameAnalysis:
+		return g.ChangeScene(&Analysis{game: g, moves: m.Moves})
 	case *messages.GameOver:
 		g.alertMessage = m.Message
 	case *messages.Joined:
@@ -83,7 +120,7 @@ func (g *Game) OnMessage(message interface{}) error {
 func (g *Game) OnTerminalEvent(event termbox.Event) error {
 	if unicode.ToUpper(event.Ch) == 'M' {
 		g.OnQuit()
-		return g.ChangeScene(&Menu{nickname: g.nickname})
+		return g.ChangeScene(&Menu{nickname: g.nickname, boardSize: g.boardSize, variant: g.variant, handicap: g.handicap, obstacles: g.obstacles, players: g.players, color: g.color, allowHints: g.allowHints})
 	}
 
 	if g.alertMessage != "" {
@@ -91,13 +128,20 @@ func (g *Game) OnTerminalEvent(event termbox.Event) error {
 	}
 
 	dx, dy := getDirectionPressed(event)
-	g.curSquareX = clamp(g.curSquareX+dx, 0, common.BoardSize)
-	g.curSquareY = clamp(g.curSquareY+dy, 0, common.BoardSize)
+	g.curSquareX = clamp(g.curSquareX+dx, 0, g.size())
+	g.curSquareY = clamp(g.curSquareY+dy, 0, g.size())
+
+	// The game is kept open after it ends, so that it can be analyzed.
+	if unicode.ToUpper(event.Ch) == 'R' && g.match.IsOver() {
+		return g.SendMessage(messages.RequestGameAnalysis{Nickname: g.nickname, Host: g.host})
+	}
 
-	if event.Key == termbox.KeyEnter && g.whoseTurn == g.player {
-		board, updated := common.ApplyMove(g.board, g.curSquareX, g.curSquareY, g.player)
-		if updated {
-			g.board = board
+	if unicode.ToUpper(event.Ch) == 'H' && g.match.Player == g.player && !g.match.IsOver() {
+		return g.SendMessage(messages.RequestHint{Nickname: g.nickname, Host: g.host})
+	}
+
+	if event.Key == termbox.KeyEnter && g.match.Player == g.player {
+		if err := g.match.Play(g.curSquareX, g.curSquareY); err == nil {
 			message := messages.PlaceDisk{
 				Nickname: g.nickname,
 				Host:     g.host,
@@ -120,15 +164,11 @@ func (g *Game) OnQuit() {
 }
 
 func (g *Game) Tick() bool {
-	if !common.GameOver(g.board) {
+	if !g.match.IsOver() {
 		return false
 	}
 
-	p1, p2 := common.KeepScore(g.board)
-	switch {
-	case g.player == 1 && p2 > p1:
-		return false
-	case g.player == 2 && p1 > p2:
+	if winner := g.match.Result.Winner; winner != 0 && winner != g.player {
 		return false
 	}
 
@@ -139,68 +179,96 @@ func (g *Game) Tick() bool {
 func (g *Game) Draw() {
 	g.drawScore()
 	draw.Draw(draw.TopRight, draw.Normal, fmt.Sprintf("Your name is %s!", strings.ToUpper(g.nickname)))
-	draw.Draw(draw.BotRight, draw.Normal, "[M] MENU  [Q] QUIT")
-	drawBoardOutline()
+	if g.match.IsOver() {
+		draw.Draw(draw.BotRight, draw.Normal, "[R] REVIEW  [M] MENU  [Q] QUIT")
+	} else {
+		draw.Draw(draw.BotRight, draw.Normal, "[H] HINT  [M] MENU  [Q] QUIT")
+	}
+	if g.variant == common.AntiOthello {
+		draw.Draw(draw.TopLeft, draw.Normal, "ANTI-OTHELLO: FEWEST DISKS WINS!")
+	}
+	drawBoardOutline(g.size())
 	g.drawDisks()
 	g.drawCursor()
 	g.confetti.draw()
 	g.drawAlert()
-	if g.player == g.whoseTurn && (g.p1Score+g.p2Score > 4) {
-		g.highlightMove(g.prevX, g.prevY)
+	if g.player == g.match.Player && g.prevX >= 0 {
+		g.highlightMove(g.prevX, g.prevY, draw.Normal)
+	}
+	if g.hintX >= 0 {
+		g.highlightMove(g.hintX, g.hintY, draw.Yellow)
 	}
 }
 
-var playerColors = map[common.Disk]draw.Color{1: draw.Magenta, 2: draw.Green}
+var playerColors = map[common.Disk]draw.Color{1: draw.Magenta, 2: draw.Green, 3: draw.Cyan, 4: draw.Yellow}
 
 func drawDisk(anchor draw.Anchor, player common.Disk) {
 	// The extra space prevents a half-circle on some terminals.
 	draw.Draw(anchor, playerColors[player], "⬤ ")
 }
 
-func (g *Game) highlightMove(x, y int) {
-	draw.Draw(draw.Offset(draw.Center, ((x+1-common.BoardSize/2)*squareWidth)-4, (y+1-common.BoardSize/2)*squareHeight), draw.Normal, "[")
-	draw.Draw(draw.Offset(draw.Center, ((x+1-common.BoardSize/2)*squareWidth)-1, (y+1-common.BoardSize/2)*squareHeight), draw.Normal, "]")
+// highlightMove draws brackets of the given color around the square (x, y).
+func (g *Game) highlightMove(x, y int, color draw.Color) {
+	offsetX, offsetY := squareOffset(g.size(), x, y)
+	draw.Draw(draw.Offset(draw.Center, offsetX-squareWidth(g.size())+1, offsetY), color, "[")
+	draw.Draw(draw.Offset(draw.Center, offsetX-1, offsetY), color, "]")
 }
 
-var (
-	squareWidth  = 5
-	squareHeight = 2
-)
+// size returns the size of the board being played. Until the server sends the board, it is the
+// size that was requested when the game was started.
+func (g *Game) size() int {
+	if size := g.match.Board.Size(); size > 0 {
+		return size
+	}
+	if g.boardSize > 0 {
+		return g.boardSize
+	}
+	return common.DefaultBoardSize
+}
 
-func (g *Game) drawScore() {
-	var p1Name, p2Name string
-	if g.player == 1 {
-		p1Name = strings.ToUpper(g.nickname)
-		p2Name = strings.ToUpper(g.opponent)
-	} else {
-		p1Name = strings.ToUpper(g.opponent)
-		p2Name = strings.ToUpper(g.nickname)
+var squareHeight = 2
+
+// squareWidth returns the width of a square on a board of the given size. Squares are narrower on
+// large boards so that the board still fits in the window.
+func squareWidth(size int) int {
+	if size > common.DefaultBoardSize {
+		return 4
 	}
+	return 5
+}
 
-	// P1 Name and Score
-	drawDisk(draw.Offset(draw.MiddleLeft, 4, -1), 1)
-	draw.Draw(draw.Offset(draw.MiddleLeft, 7, -1), draw.Normal, fmt.Sprintf("%s: %-2d", p1Name, g.p1Score))
+// squareOffset returns the position of the bottom-right corner of the square (x, y), relative to
+// the center of the screen.
+func squareOffset(size, x, y int) (offsetX, offsetY int) {
+	return (x + 1 - size/2) * squareWidth(size), (y + 1 - size/2) * squareHeight
+}
 
-	// P2 Name and Score
-	drawDisk(draw.Offset(draw.MiddleLeft, 4, 1), 2)
-	draw.Draw(draw.Offset(draw.MiddleLeft, 7, 1), draw.Normal, fmt.Sprintf("%s: %-2d", p2Name, g.p2Score))
+func (g *Game) drawScore() {
+	// Each player's name and score, one below the other. Every player other than this one is
+	// shown with the opponent's name, since only solo games have more than two players.
+	for i, score := range g.scores {
+		player := common.Disk(i + 1)
+
+		name := strings.ToUpper(g.opponent)
+		if player == g.player {
+			name = strings.ToUpper(g.nickname)
+		}
+
+		drawDisk(draw.Offset(draw.MiddleLeft, 4, 2*i-1), player)
+		draw.Draw(draw.Offset(draw.MiddleLeft, 7, 2*i-1), draw.Normal, fmt.Sprintf("%s: %-2d", name, score))
+	}
 
 	// Current turn indicator
-	if !common.GameOver(g.board) {
-		var yOffset int
-		if g.whoseTurn == 1 {
-			yOffset = 0
-		} else {
-			yOffset = 2
-		}
-		draw.Draw(draw.Offset(draw.MiddleLeft, 4, yOffset), draw.Normal, "﹌")
+	if !g.match.IsOver() {
+		draw.Draw(draw.Offset(draw.MiddleLeft, 4, 2*int(g.match.Player)-2), draw.Normal, "﹌")
 	}
 }
 
-func drawBoardOutline() {
+func drawBoardOutline(size int) {
 	var (
-		boardWidth  = common.BoardSize * squareWidth
-		boardHeight = common.BoardSize * squareHeight
+		squareWidth = squareWidth(size)
+		boardWidth  = size * squareWidth
+		boardHeight = size * squareHeight
 	)
 
 	// Outline
@@ -260,29 +328,34 @@ func drawBoardOutline() {
 }
 
 func (g *Game) drawDisks() {
-	for i := 0; i < common.BoardSize; i++ {
-		for j := 0; j < common.BoardSize; j++ {
-			player := g.board[i][j]
+	size := g.match.Board.Size()
+
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			player := g.match.Board.At(i, j)
 			if player == 0 {
 				continue
 			}
 
-			x := (i+1-common.BoardSize/2)*squareWidth - 2
-			y := (j + 1 - common.BoardSize/2) * squareHeight
+			x, y := squareOffset(size, i, j)
+
+			if player == common.Blocked {
+				draw.Draw(draw.Offset(draw.Center, x-3, y), draw.Normal, "▓▓▓")
+				continue
+			}
 
-			drawDisk(draw.Offset(draw.Center, x, y), player)
+			drawDisk(draw.Offset(draw.Center, x-2, y), player)
 		}
 	}
 }
 
 func (g *Game) drawCursor() {
-	if common.GameOver(g.board) || g.whoseTurn != g.player || g.alertMessage != "" {
+	if g.match.IsOver() || g.match.Player != g.player || g.alertMessage != "" {
 		termbox.HideCursor()
 	} else {
-		x := (g.curSquareX+1-common.BoardSize/2)*squareWidth - 3
-		y := (g.curSquareY + 1 - common.BoardSize/2) * squareHeight
+		x, y := squareOffset(g.size(), g.curSquareX, g.curSquareY)
 
-		draw.SetCursor(draw.Offset(draw.Center, x, y))
+		draw.SetCursor(draw.Offset(draw.Center, x-3, y))
 	}
 }
 
@@ -13,9 +13,10 @@ import (
 
 type Join struct {
 	scene
-	nickname string
-	hosts    []string
-	selected int
+	nickname   string
+	allowHints bool
+	hosts      []string
+	selected   int
 }
 
 func (j *Join) Setup(changeScene ChangeScene, sendMessage SendMessage) error {
@@ -39,7 +40,7 @@ func (j *Join) OnMessage(message interface{}) error {
 
 func (j *Join) OnTerminalEvent(event termbox.Event) error {
 	if event.Key == termbox.KeyEnter && len(j.hosts) > 0 {
-		return j.ChangeScene(&Game{player: 2, multiplayer: true, nickname: j.nickname, host: j.hosts[j.selected], opponent: j.hosts[j.selected]})
+		return j.ChangeScene(&Game{player: 2, multiplayer: true, allowHints: j.allowHints, nickname: j.nickname, host: j.hosts[j.selected], opponent: j.hosts[j.selected]})
 	}
 	_, dy := getDirectionPressed(event)
 	switch {
@@ -50,7 +51,7 @@ func (j *Join) OnTerminalEvent(event termbox.Event) error {
 	}
 
 	if unicode.ToUpper(event.Ch) == 'M' {
-		return j.ChangeScene(&Menu{nickname: j.nickname})
+		return j.ChangeScene(&Menu{nickname: j.nickname, allowHints: j.allowHints})
 	}
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/nsf/termbox-go"
 
 	"github.com/armsnyder/othelgo/pkg/client/draw"
+	"github.com/armsnyder/othelgo/pkg/common"
+	"github.com/armsnyder/othelgo/pkg/messages"
 )
 
 const (
@@ -16,12 +18,29 @@ const (
 	buttonHostGame
 	buttonJoinGame
 	buttonChangeName
+	buttonBoardSize
+	buttonVariant
+	buttonHandicap
+	buttonObstacles
+	buttonPlayers
+	buttonColor
+	buttonHints
 )
 
 type Menu struct {
 	scene
-	button   int
-	nickname string
+	button    int
+	nickname  string
+	boardSize int
+	variant   common.Variant
+	handicap  common.Handicap
+	obstacles int
+	players   int
+	color     messages.Color
+
+	// allowHints is whether the user allows hints in multiplayer games. Hints are always allowed
+	// in solo games.
+	allowHints bool
 }
 
 func (m *Menu) OnTerminalEvent(event termbox.Event) error {
@@ -39,11 +58,25 @@ func (m *Menu) OnTerminalEvent(event termbox.Event) error {
 		switch m.button {
 		case buttonEasy, buttonNormal, buttonHard:
 			m.button = buttonHostGame
-		case buttonHostGame, buttonJoinGame:
+		case buttonHostGame, buttonJoinGame, buttonBoardSize, buttonVariant, buttonHandicap, buttonObstacles, buttonPlayers, buttonColor, buttonHints:
 			m.button = buttonChangeName
 		}
 	case dy == -1:
 		switch m.button {
+		case buttonEasy:
+			m.button = buttonHints
+		case buttonHints:
+			m.button = buttonColor
+		case buttonColor:
+			m.button = buttonPlayers
+		case buttonPlayers:
+			m.button = buttonObstacles
+		case buttonObstacles:
+			m.button = buttonHandicap
+		case buttonHandicap:
+			m.button = buttonVariant
+		case buttonVariant, buttonBoardSize:
+			m.button = buttonBoardSize
 		case buttonNormal:
 			m.button = buttonEasy
 		case buttonHard:
@@ -55,6 +88,20 @@ func (m *Menu) OnTerminalEvent(event termbox.Event) error {
 		}
 	case dy == 1:
 		switch m.button {
+		case buttonBoardSize:
+			m.button = buttonVariant
+		case buttonVariant:
+			m.button = buttonHandicap
+		case buttonHandicap:
+			m.button = buttonObstacles
+		case buttonObstacles:
+			m.button = buttonPlayers
+		case buttonPlayers:
+			m.button = buttonColor
+		case buttonColor:
+			m.button = buttonHints
+		case buttonHints:
+			m.button = buttonEasy
 		case buttonEasy:
 			m.button = buttonNormal
 		case buttonNormal:
@@ -69,30 +116,126 @@ func (m *Menu) OnTerminalEvent(event termbox.Event) error {
 	if event.Key == termbox.KeyEnter {
 		switch m.button {
 		case buttonEasy:
-			return m.ChangeScene(&Game{player: 1, difficulty: 0, nickname: m.nickname, host: m.nickname, opponent: "AI EASY"})
+			return m.ChangeScene(&Game{player: 1, difficulty: 0, boardSize: m.boardSize, variant: m.variant, handicap: m.handicap, obstacles: m.obstacles, players: m.players, color: m.color, nickname: m.nickname, host: m.nickname, opponent: "AI EASY"})
 		case buttonNormal:
-			return m.ChangeScene(&Game{player: 1, difficulty: 1, nickname: m.nickname, host: m.nickname, opponent: "AI NORMAL"})
+			return m.ChangeScene(&Game{player: 1, difficulty: 1, boardSize: m.boardSize, variant: m.variant, handicap: m.handicap, obstacles: m.obstacles, players: m.players, color: m.color, nickname: m.nickname, host: m.nickname, opponent: "AI NORMAL"})
 		case buttonHard:
-			return m.ChangeScene(&Game{player: 1, difficulty: 2, nickname: m.nickname, host: m.nickname, opponent: "AI HARD"})
+			return m.ChangeScene(&Game{player: 1, difficulty: 2, boardSize: m.boardSize, variant: m.variant, handicap: m.handicap, obstacles: m.obstacles, players: m.players, color: m.color, nickname: m.nickname, host: m.nickname, opponent: "AI HARD"})
 		case buttonHostGame:
-			return m.ChangeScene(&Game{player: 1, multiplayer: true, nickname: m.nickname, host: m.nickname, opponent: "[OPPONENT]"})
+			return m.ChangeScene(&Game{player: 1, multiplayer: true, boardSize: m.boardSize, variant: m.variant, handicap: m.handicap, obstacles: m.obstacles, players: m.players, allowHints: m.allowHints, nickname: m.nickname, host: m.nickname, opponent: "[OPPONENT]"})
 		case buttonJoinGame:
 			// return m.ChangeScene(&Game{player: 2, multiplayer: true, nickname: m.nickname})
-			return m.ChangeScene(&Join{nickname: m.nickname})
+			return m.ChangeScene(&Join{nickname: m.nickname, allowHints: m.allowHints})
 		case buttonChangeName:
 			return m.ChangeScene(&Nickname{ChangeNickname: true})
+		case buttonBoardSize:
+			m.boardSize = m.nextBoardSize()
+		case buttonVariant:
+			m.variant = m.nextVariant()
+		case buttonHandicap:
+			m.handicap = m.nextHandicap()
+		case buttonObstacles:
+			m.obstacles = m.nextObstacles()
+		case buttonPlayers:
+			m.players = m.nextPlayers()
+		case buttonColor:
+			m.color = m.nextColor()
+		case buttonHints:
+			m.allowHints = !m.allowHints
 		}
 	}
 
 	return nil
 }
 
+// nextBoardSize returns the board size after the one that is currently selected, wrapping around
+// to the smallest size.
+func (m *Menu) nextBoardSize() int {
+	for i, size := range common.BoardSizes {
+		if size == m.selectedBoardSize() {
+			return common.BoardSizes[(i+1)%len(common.BoardSizes)]
+		}
+	}
+	return common.DefaultBoardSize
+}
+
+// nextVariant returns the variant after the one that is currently selected, wrapping around to
+// the standard game.
+func (m *Menu) nextVariant() common.Variant {
+	for i, variant := range common.Variants {
+		if variant == m.variant {
+			return common.Variants[(i+1)%len(common.Variants)]
+		}
+	}
+	return common.Standard
+}
+
+// nextHandicap returns the handicap after the one that is currently selected, wrapping around to
+// no handicap.
+func (m *Menu) nextHandicap() common.Handicap {
+	return common.Handicaps[(m.handicap.Corners()+1)%len(common.Handicaps)]
+}
+
+// obstacleCounts are the numbers of obstacles that can be chosen in the menu.
+var obstacleCounts = []int{0, common.MaxObstacles / 2, common.MaxObstacles}
+
+// nextObstacles returns the number of obstacles after the one that is currently selected, wrapping
+// around to none.
+func (m *Menu) nextObstacles() int {
+	for i, n := range obstacleCounts {
+		if n == m.obstacles {
+			return obstacleCounts[(i+1)%len(obstacleCounts)]
+		}
+	}
+	return 0
+}
+
+// nextPlayers returns the number of players after the one that is currently selected, wrapping
+// around to two players.
+func (m *Menu) nextPlayers() int {
+	return (m.selectedPlayers()-1)%(common.MaxPlayers-1) + 2
+}
+
+// colors are the colors that the human can play in a solo game, starting with the default.
+var colors = []messages.Color{messages.Black, messages.White, messages.RandomColor}
+
+// nextColor returns the color after the one that is currently selected, wrapping around to black.
+func (m *Menu) nextColor() messages.Color {
+	for i, color := range colors {
+		if color == m.selectedColor() {
+			return colors[(i+1)%len(colors)]
+		}
+	}
+	return messages.Black
+}
+
+func (m *Menu) selectedColor() messages.Color {
+	if m.color == "" {
+		return messages.Black
+	}
+	return m.color
+}
+
+func (m *Menu) selectedPlayers() int {
+	if m.players == 0 {
+		return 2
+	}
+	return m.players
+}
+
+func (m *Menu) selectedBoardSize() int {
+	if m.boardSize == 0 {
+		return common.DefaultBoardSize
+	}
+	return m.boardSize
+}
+
 func (m *Menu) Draw() {
 	drawSplash()
 
 	draw.Draw(draw.TopRight, draw.Normal, fmt.Sprintf("Did you know? Your name is %s!", strings.ToUpper(m.nickname)))
 
-	buttonColors := [6]draw.Color{draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal}
+	buttonColors := [13]draw.Color{draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal, draw.Normal}
 	buttonColors[m.button] = draw.Inverted
 
 	multiplayerButtonColor := draw.Normal
@@ -115,4 +258,16 @@ func (m *Menu) Draw() {
 	draw.Draw(draw.Offset(draw.CenterLeft, -1, 3), singleplayerButtonColor, "[ SINGLEPLAYER ]")
 	draw.Draw(multiplayerOffset, multiplayerButtonColor, "[ MULTIPLAYER ]")
 	draw.Draw(draw.Offset(draw.TopRight, 0, 2), buttonColors[buttonChangeName], "[ CHANGE NAME ]")
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 2), buttonColors[buttonBoardSize], fmt.Sprintf("[ BOARD %dx%d ]", m.selectedBoardSize(), m.selectedBoardSize()))
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 4), buttonColors[buttonVariant], fmt.Sprintf("[ %s ]", strings.ToUpper(m.variant.String())))
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 6), buttonColors[buttonHandicap], fmt.Sprintf("[ %s ]", strings.ToUpper(m.handicap.String())))
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 8), buttonColors[buttonObstacles], fmt.Sprintf("[ %d OBSTACLES ]", m.obstacles))
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 10), buttonColors[buttonPlayers], fmt.Sprintf("[ %d PLAYERS ]", m.selectedPlayers()))
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 12), buttonColors[buttonColor], fmt.Sprintf("[ PLAY %s ]", strings.ToUpper(string(m.selectedColor()))))
+
+	hints := "NO"
+	if m.allowHints {
+		hints = "YES"
+	}
+	draw.Draw(draw.Offset(draw.TopLeft, 0, 14), buttonColors[buttonHints], fmt.Sprintf("[ MULTIPLAYER HINTS: %s ]", hints))
 }
@@ -41,6 +41,13 @@ func min(a, b int) int {
 	return b
 }
 
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func clamp(val, min, max int) int {
 	switch {
 	case val < min:
@@ -0,0 +1,236 @@
+package common
+
+import "math/bits"
+
+// Bitboard is a set of squares on a board of DefaultBoardSize packed into 64 bits. Square (x, y) is
+// stored in bit y*8+x, so bit 0 is the top-left corner and bit 63 is the bottom-right corner.
+type Bitboard uint64
+
+// bitboardSize is the width and height of the board that a Bitboard represents.
+const bitboardSize = DefaultBoardSize
+
+const (
+	notFileA Bitboard = 0xfefefefefefefefe // Every square except those where x == 0.
+	notFileH Bitboard = 0x7f7f7f7f7f7f7f7f // Every square except those where x == 7.
+)
+
+// direction is one of the 8 compass directions a line of disks can be flipped along, expressed as
+// a bit shift plus a mask that stops the shift from wrapping around the edge of the board.
+type direction struct {
+	shift int
+	mask  Bitboard
+}
+
+var directions = [8]direction{
+	{shift: 1, mask: notFileA},      // East
+	{shift: -1, mask: notFileH},     // West
+	{shift: 8, mask: ^Bitboard(0)},  // South
+	{shift: -8, mask: ^Bitboard(0)}, // North
+	{shift: 9, mask: notFileA},      // South-east
+	{shift: 7, mask: notFileH},      // South-west
+	{shift: -7, mask: notFileA},     // North-east
+	{shift: -9, mask: notFileH},     // North-west
+}
+
+func (d direction) apply(b Bitboard) Bitboard {
+	if d.shift > 0 {
+		return (b << uint(d.shift)) & d.mask
+	}
+	return (b >> uint(-d.shift)) & d.mask
+}
+
+// SquareBit returns a Bitboard containing only the square (x, y), or an empty Bitboard if the
+// square is out of bounds.
+func SquareBit(x, y int) Bitboard {
+	if x < 0 || x >= bitboardSize || y < 0 || y >= bitboardSize {
+		return 0
+	}
+	return 1 << uint(y*bitboardSize+x)
+}
+
+// Has returns true if the square (x, y) is in the set.
+func (b Bitboard) Has(x, y int) bool {
+	return b&SquareBit(x, y) != 0
+}
+
+// Count returns the number of squares in the set.
+func (b Bitboard) Count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// Squares returns the coordinates of every square in the set, in bit order.
+func (b Bitboard) Squares() [][2]int {
+	squares := make([][2]int, 0, b.Count())
+	for b != 0 {
+		i := bits.TrailingZeros64(uint64(b))
+		squares = append(squares, [2]int{i % bitboardSize, i / bitboardSize})
+		b &= b - 1
+	}
+	return squares
+}
+
+// Position is a bitboard representation of a Board of DefaultBoardSize. It is much cheaper to
+// search than a Board, so it is intended for use by the AI and other code that needs to look at
+// many positions.
+type Position struct {
+	P1      Bitboard
+	P2      Bitboard
+	Blocked Bitboard
+}
+
+// NewPosition converts a Board to a Position. It returns false if the board is not of
+// DefaultBoardSize, since only those boards fit in a Bitboard, or if it has disks of more than two
+// players.
+func NewPosition(board Board) (Position, bool) {
+	var p Position
+
+	if board.Size() != bitboardSize {
+		return p, false
+	}
+
+	for x := 0; x < bitboardSize; x++ {
+		for y := 0; y < bitboardSize; y++ {
+			switch board.disks[x][y] {
+			case Player1:
+				p.P1 |= SquareBit(x, y)
+			case Player2:
+				p.P2 |= SquareBit(x, y)
+			case Blocked:
+				p.Blocked |= SquareBit(x, y)
+			case Player3, Player4:
+				return Position{}, false
+			}
+		}
+	}
+
+	return p, true
+}
+
+// Board converts the Position back to a Board.
+func (p Position) Board() Board {
+	board := NewBoard(bitboardSize)
+	for x := 0; x < bitboardSize; x++ {
+		for y := 0; y < bitboardSize; y++ {
+			switch {
+			case p.P1.Has(x, y):
+				board.set(x, y, Player1)
+			case p.P2.Has(x, y):
+				board.set(x, y, Player2)
+			case p.Blocked.Has(x, y):
+				board.set(x, y, Blocked)
+			}
+		}
+	}
+	return board
+}
+
+// Disks returns the squares occupied by the player.
+func (p Position) Disks(player Disk) Bitboard {
+	switch player {
+	case Player1:
+		return p.P1
+	case Player2:
+		return p.P2
+	}
+	return 0
+}
+
+// Empty returns the squares that are neither occupied by a disk nor blocked.
+func (p Position) Empty() Bitboard {
+	return ^(p.P1 | p.P2 | p.Blocked)
+}
+
+// sides returns the disks of the player and of their opponent.
+func (p Position) sides(player Disk) (own, opp Bitboard) {
+	switch player {
+	case Player1:
+		return p.P1, p.P2
+	case Player2:
+		return p.P2, p.P1
+	}
+	return 0, 0
+}
+
+// LegalMoves returns the set of squares where the player can place a disk.
+func (p Position) LegalMoves(player Disk) Bitboard {
+	own, opp := p.sides(player)
+	empty := p.Empty()
+
+	var moves Bitboard
+	for _, d := range directions {
+		// Find runs of opponent disks that start next to one of the player's disks, then extend
+		// them as far as they go. A run can be at most 6 disks long.
+		run := d.apply(own) & opp
+		for i := 0; i < bitboardSize-3; i++ {
+			run |= d.apply(run) & opp
+		}
+		moves |= d.apply(run) & empty
+	}
+
+	return moves
+}
+
+// HasMoves returns true if the player has at least one legal move.
+func (p Position) HasMoves(player Disk) bool {
+	return p.LegalMoves(player) != 0
+}
+
+// Flips returns the set of opponent disks that would be flipped if the player placed a disk on the
+// square (x, y). It is empty if the move is illegal.
+func (p Position) Flips(x, y int, player Disk) Bitboard {
+	move := SquareBit(x, y)
+	if move&p.Empty() == 0 {
+		return 0
+	}
+
+	own, opp := p.sides(player)
+
+	var flips Bitboard
+	for _, d := range directions {
+		var line Bitboard
+		next := d.apply(move)
+		for next&opp != 0 {
+			line |= next
+			next = d.apply(next)
+		}
+		if next&own != 0 {
+			flips |= line
+		}
+	}
+
+	return flips
+}
+
+// ApplyMove is the Position equivalent of the package-level ApplyMove function. It returns the new
+// Position and true if the move was legal, or the unchanged Position and false otherwise.
+func (p Position) ApplyMove(x, y int, player Disk) (Position, bool) {
+	flips := p.Flips(x, y, player)
+	if flips == 0 {
+		return p, false
+	}
+
+	placed := flips | SquareBit(x, y)
+	switch player {
+	case Player1:
+		p.P1 |= placed
+		p.P2 &^= flips
+	case Player2:
+		p.P2 |= placed
+		p.P1 &^= flips
+	}
+
+	return p, true
+}
+
+// Score is the Position equivalent of KeepScore.
+func (p Position) Score() (p1 int, p2 int) {
+	return p.P1.Count(), p.P2.Count()
+}
+
+// GameOver is the Position equivalent of the package-level GameOver function.
+func (p Position) GameOver() bool {
+	if p == (Position{}) {
+		return false
+	}
+	return !(p.HasMoves(Player1) || p.HasMoves(Player2))
+}
@@ -1,36 +1,168 @@
 package common
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultBoardSize = 8
+	MaxBoardSize     = 12
+)
 
-const BoardSize = 8
+// BoardSizes are the sizes of board that a game can be played on.
+var BoardSizes = []int{6, DefaultBoardSize, 10, MaxBoardSize}
 
 type Disk uint8
 
 const (
 	Player1 = Disk(1)
 	Player2 = Disk(2)
+	Player3 = Disk(3)
+	Player4 = Disk(4)
+
+	// Blocked is an obstacle on a square. Nobody can play on it, and it stops lines of disks from
+	// being flipped, like the edge of the board.
+	Blocked = Disk(MaxPlayers + 1)
 )
 
-type Board [BoardSize][BoardSize]Disk
+// MaxPlayers is the most players that can play a game. Players are numbered from 1, in the order
+// that they take turns.
+const MaxPlayers = 4
+
+// Board is a square game board of any size in BoardSizes. The zero value is an empty board with a
+// size of zero, which is used to mean that there is no game yet.
+type Board struct {
+	size  int
+	disks [MaxBoardSize][MaxBoardSize]Disk
+
+	// hash is the Zobrist hash of the disks, which is kept up to date by set.
+	hash uint64
+}
+
+// NewBoard returns an empty board of the given size.
+func NewBoard(size int) Board {
+	if size < 0 || size > MaxBoardSize {
+		panic(fmt.Errorf("common: invalid board size %d", size))
+	}
+	return Board{size: size}
+}
+
+// ValidBoardSize returns true if size is one of BoardSizes.
+func ValidBoardSize(size int) bool {
+	for _, s := range BoardSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the width and height of the board.
+func (b Board) Size() int {
+	return b.size
+}
+
+// At returns the disk at (x, y), or zero if the square is empty or out of bounds.
+func (b Board) At(x, y int) Disk {
+	if !b.InBounds(x, y) {
+		return 0
+	}
+	return b.disks[x][y]
+}
+
+// Set places a disk at (x, y). It panics if the square is out of bounds.
+func (b *Board) Set(x, y int, disk Disk) {
+	if !b.InBounds(x, y) {
+		panic(fmt.Errorf("common: square (%d, %d) is out of bounds on a board of size %d", x, y, b.size))
+	}
+	b.set(x, y, disk)
+}
+
+// set places a disk at (x, y) and updates the hash of the board. The square must be in bounds.
+func (b *Board) set(x, y int, disk Disk) {
+	b.hash ^= zobristKey(x, y, b.disks[x][y]) ^ zobristKey(x, y, disk)
+	b.disks[x][y] = disk
+}
+
+// InBounds returns true if (x, y) is a square on the board.
+func (b Board) InBounds(x, y int) bool {
+	return x >= 0 && x < b.size && y >= 0 && y < b.size
+}
+
+// hasEmpty returns true if any square on the board is empty.
+func (b Board) hasEmpty() bool {
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if b.disks[x][y] == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Empties returns the number of empty squares on the board, not counting blocked squares.
+func (b Board) Empties() int {
+	empties := 0
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if b.disks[x][y] == 0 {
+				empties++
+			}
+		}
+	}
+	return empties
+}
 
 func (b Board) String() string {
 	// This function makes Board implement fmt.Stringer so that it renders visually in test outputs.
 	var sb strings.Builder
-	for y := 0; y < BoardSize; y++ {
+	for y := 0; y < b.size; y++ {
 		sb.WriteRune('\n')
-		for x := 0; x < BoardSize; x++ {
-			var ch rune
-			switch b[x][y] {
-			case 0:
-				ch = '_'
-			case 1:
-				ch = 'x'
-			case 2:
-				ch = 'o'
-			}
-			sb.WriteRune(ch)
+		for x := 0; x < b.size; x++ {
+			sb.WriteRune(diskRune(b.disks[x][y]))
 		}
 	}
 
 	return sb.String()
 }
+
+// MarshalJSON encodes the board as an array of columns, each of which is an array of disks.
+func (b Board) MarshalJSON() ([]byte, error) {
+	columns := make([][]int, b.size)
+	for x := range columns {
+		columns[x] = make([]int, b.size)
+		for y := range columns[x] {
+			columns[x][y] = int(b.disks[x][y])
+		}
+	}
+	return json.Marshal(columns)
+}
+
+// UnmarshalJSON decodes a board that was encoded by MarshalJSON. The size of the board is the
+// number of columns.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	var columns [][]Disk
+	if err := json.Unmarshal(data, &columns); err != nil {
+		return err
+	}
+
+	if len(columns) > MaxBoardSize {
+		return fmt.Errorf("board size %d is larger than the maximum of %d", len(columns), MaxBoardSize)
+	}
+
+	*b = NewBoard(len(columns))
+
+	for x, column := range columns {
+		if len(column) != b.size {
+			return fmt.Errorf("board column %d has length %d but the board has size %d", x, len(column), b.size)
+		}
+		for y, disk := range column {
+			b.set(x, y, disk)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package common_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestBoardJSON(t *testing.T) {
+	board := buildSizedTestBoard(6, []move{{0, 1}}, []move{{5, 2}})
+
+	b, err := json.Marshal(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[[0,1,0,0,0,0],[0,0,0,0,0,0],[0,0,0,0,0,0],[0,0,0,0,0,0],[0,0,0,0,0,0],[0,0,2,0,0,0]]`
+	if string(b) != want {
+		t.Errorf("json.Marshal() got %s, want %s", b, want)
+	}
+
+	var got Board
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != board {
+		t.Errorf("json.Unmarshal() got %v, want %v", got, board)
+	}
+}
+
+func TestBoardJSONInvalid(t *testing.T) {
+	var board Board
+
+	if err := json.Unmarshal([]byte(`[[0,0],[0]]`), &board); err == nil {
+		t.Errorf("json.Unmarshal() of a ragged board got no error")
+	}
+}
+
+func TestNewMatchSizes(t *testing.T) {
+	for _, size := range BoardSizes {
+		m := NewMatch(size)
+
+		if got := m.Board.Size(); got != size {
+			t.Errorf("NewMatch(%d) got board size %d", size, got)
+		}
+
+		if scores := m.Scores(); !reflect.DeepEqual(scores, []int{2, 2}) {
+			t.Errorf("NewMatch(%d) got scores %v, want [2 2]", size, scores)
+		}
+
+		if got := len(m.LegalMoves()); got != 4 {
+			t.Errorf("NewMatch(%d) got %d legal moves, want 4", size, got)
+		}
+
+		_, ok := NewPosition(m.Board)
+		if want := size == DefaultBoardSize; ok != want {
+			t.Errorf("NewPosition() of a board of size %d got ok = %v, want %v", size, ok, want)
+		}
+	}
+}
+
+func TestApplyMoveSmallBoard(t *testing.T) {
+	// On a bigger board, player 1 could flip both of player 2's disks by playing (6, 6).
+	board := buildSizedTestBoard(6, []move{{3, 3}}, []move{{4, 4}, {5, 5}})
+
+	if _, updated := ApplyMove(board, 6, 6, Player1); updated {
+		t.Errorf("ApplyMove() off the edge of the board got updated = true, want false")
+	}
+
+	if HasMoves(board, Player1) {
+		t.Errorf("HasMoves() got true, want false")
+	}
+}
@@ -0,0 +1,207 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Boards can be written as text in two forms, which ParseBoard reads.
+//
+// The grid form is the output of Board.String: one row per line from top to bottom, with a
+// character for each square. It may be followed by a line with a single disk character, which is
+// the player to move. For example:
+//
+//	________
+//	________
+//	________
+//	___xo___
+//	___ox___
+//	________
+//	________
+//	________
+//	x
+//
+// The compact form is a single line, similar to the FEN notation of chess. The rows are separated
+// by slashes, and a run of empty squares is written as its length. The player to move may follow
+// after a space. The same board is written as:
+//
+//	8/8/8/3xo3/3ox3/8/8/8 x
+
+// diskRunes are the characters of each disk in the text forms of a board, indexed by disk.
+var diskRunes = [Blocked + 1]rune{'_', 'x', 'o', '+', '*', '#'}
+
+// diskRune returns the character of a disk, or '?' if the disk is not valid.
+func diskRune(disk Disk) rune {
+	if int(disk) >= len(diskRunes) {
+		return '?'
+	}
+	return diskRunes[disk]
+}
+
+// parseDisk returns the disk of a character. Empty squares may also be written as '.' or '-'.
+func parseDisk(ch rune) (Disk, bool) {
+	switch ch {
+	case '.', '-':
+		return 0, true
+	}
+
+	for disk, r := range diskRunes {
+		if r == ch {
+			return Disk(disk), true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseBoard reads a board in either text form, and returns the player to move, which is player 1
+// if the text does not say.
+func ParseBoard(s string) (Board, Disk, error) {
+	s = strings.TrimSpace(s)
+
+	var (
+		rows   []string
+		player string
+	)
+
+	if strings.Contains(s, "/") {
+		fields := strings.Fields(s)
+		if len(fields) > 2 {
+			return Board{}, 0, fmt.Errorf("unexpected %q after the board", strings.Join(fields[2:], " "))
+		}
+		if len(fields) == 2 {
+			player = fields[1]
+		}
+
+		var err error
+		if rows, err = expandRows(strings.Split(fields[0], "/")); err != nil {
+			return Board{}, 0, err
+		}
+	} else {
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				rows = append(rows, line)
+			}
+		}
+
+		if len(rows) > 0 && len([]rune(rows[len(rows)-1])) == 1 {
+			player = rows[len(rows)-1]
+			rows = rows[:len(rows)-1]
+		}
+	}
+
+	board, err := parseRows(rows)
+	if err != nil {
+		return Board{}, 0, err
+	}
+
+	if player == "" {
+		return board, Player1, nil
+	}
+
+	disk, ok := parseDisk([]rune(player)[0])
+	if len([]rune(player)) != 1 || !ok || disk < Player1 || disk > MaxPlayers {
+		return Board{}, 0, fmt.Errorf("invalid player to move %q", player)
+	}
+
+	return board, disk, nil
+}
+
+// expandRows replaces the runs of empty squares in rows of the compact form with one character
+// per square.
+func expandRows(rows []string) ([]string, error) {
+	expanded := make([]string, len(rows))
+
+	for i, row := range rows {
+		var sb strings.Builder
+
+		for j := 0; j < len(row); {
+			if !unicode.IsDigit(rune(row[j])) {
+				sb.WriteByte(row[j])
+				j++
+				continue
+			}
+
+			k := j
+			for k < len(row) && unicode.IsDigit(rune(row[k])) {
+				k++
+			}
+
+			n, err := strconv.Atoi(row[j:k])
+			if err != nil || n > MaxBoardSize {
+				return nil, fmt.Errorf("row %d: invalid run of empty squares %q", i+1, row[j:k])
+			}
+
+			sb.WriteString(strings.Repeat(string(diskRunes[0]), n))
+			j = k
+		}
+
+		expanded[i] = sb.String()
+	}
+
+	return expanded, nil
+}
+
+// parseRows reads a board from one string per row, with one character per square.
+func parseRows(rows []string) (Board, error) {
+	size := len(rows)
+	if !ValidBoardSize(size) {
+		return Board{}, fmt.Errorf("invalid board size %d", size)
+	}
+
+	board := NewBoard(size)
+
+	for y, row := range rows {
+		squares := []rune(row)
+		if len(squares) != size {
+			return Board{}, fmt.Errorf("row %d has %d squares but the board has size %d", y+1, len(squares), size)
+		}
+
+		for x, ch := range squares {
+			disk, ok := parseDisk(ch)
+			if !ok {
+				return Board{}, fmt.Errorf("row %d: invalid square %q", y+1, ch)
+			}
+			board.set(x, y, disk)
+		}
+	}
+
+	return board, nil
+}
+
+// FormatBoard writes a board and the player to move in the compact text form.
+func FormatBoard(board Board, player Disk) string {
+	var sb strings.Builder
+
+	for y := 0; y < board.size; y++ {
+		if y > 0 {
+			sb.WriteRune('/')
+		}
+
+		empties := 0
+		for x := 0; x < board.size; x++ {
+			disk := board.disks[x][y]
+			if disk == 0 {
+				empties++
+				continue
+			}
+
+			if empties > 0 {
+				sb.WriteString(strconv.Itoa(empties))
+				empties = 0
+			}
+			sb.WriteRune(diskRune(disk))
+		}
+
+		if empties > 0 {
+			sb.WriteString(strconv.Itoa(empties))
+		}
+	}
+
+	sb.WriteRune(' ')
+	sb.WriteRune(diskRune(player))
+
+	return sb.String()
+}
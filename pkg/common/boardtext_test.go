@@ -0,0 +1,123 @@
+package common_test
+
+import (
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestParseBoard(t *testing.T) {
+	start := NewMatch(DefaultBoardSize).Board
+
+	tests := []struct {
+		name       string
+		text       string
+		wantBoard  Board
+		wantPlayer Disk
+	}{
+		{
+			name: "grid",
+			text: `
+				________
+				________
+				________
+				___xo___
+				___ox___
+				________
+				________
+				________`,
+			wantBoard:  start,
+			wantPlayer: Player1,
+		},
+		{
+			name: "grid with player to move",
+			text: `
+				______
+				______
+				__xo#_
+				__ox__
+				......
+				------
+				o`,
+			wantBoard: func() Board {
+				board := NewMatch(6).Board
+				board.Set(4, 2, Blocked)
+				return board
+			}(),
+			wantPlayer: Player2,
+		},
+		{
+			name:       "compact",
+			text:       "8/8/8/3xo3/3ox3/8/8/8",
+			wantBoard:  start,
+			wantPlayer: Player1,
+		},
+		{
+			name:       "compact with player to move",
+			text:       "10/10/10/10/4xo4/4ox4/10/10/10/10 o",
+			wantBoard:  NewMatch(10).Board,
+			wantPlayer: Player2,
+		},
+		{
+			name:       "compact four players",
+			text:       "8/8/2xo+*2/2o+*x2/2+*xo2/2*xo+2/8/8 *",
+			wantBoard:  NewMatchForPlayers(DefaultBoardSize, 4).Board,
+			wantPlayer: Player4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, player, err := ParseBoard(tt.text)
+			if err != nil {
+				t.Fatalf("ParseBoard() got error: %v", err)
+			}
+			if board != tt.wantBoard {
+				t.Errorf("ParseBoard() got board %v, want %v", board, tt.wantBoard)
+			}
+			if player != tt.wantPlayer {
+				t.Errorf("ParseBoard() got player %d, want %d", player, tt.wantPlayer)
+			}
+		})
+	}
+}
+
+func TestParseBoardInvalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"8/8/8/3xo3/3ox3/8/8",
+		"8/8/8/3ox3/3xo4/8/8/8",
+		"8/8/8/3oz3/3xo3/8/8/8",
+		"8/8/8/3xo3/3ox3/8/8/8 #",
+		"8/8/8/3xo3/3ox3/8/8/8 x o",
+		"7/7/7/7/7/7/7",
+		"______\n______\n______\n______\n______\n_____",
+	} {
+		if _, _, err := ParseBoard(text); err == nil {
+			t.Errorf("ParseBoard(%q) got no error", text)
+		}
+	}
+}
+
+func TestBoardTextRoundTrip(t *testing.T) {
+	for i, board := range randomPositions(t, 14) {
+		player := Disk(i%2 + 1)
+
+		got, gotPlayer, err := ParseBoard(board.String())
+		if err != nil || got != board || gotPlayer != Player1 {
+			t.Fatalf("ParseBoard() of Board.String() got %v, %d, %v, want %v", got, gotPlayer, err, board)
+		}
+
+		text := FormatBoard(board, player)
+		got, gotPlayer, err = ParseBoard(text)
+		if err != nil || got != board || gotPlayer != player {
+			t.Fatalf("ParseBoard(%q) got %v, %d, %v, want %v, %d", text, got, gotPlayer, err, board, player)
+		}
+	}
+}
+
+func TestFormatBoard(t *testing.T) {
+	if got, want := FormatBoard(NewMatch(DefaultBoardSize).Board, Player1), "8/8/8/3xo3/3ox3/8/8/8 x"; got != want {
+		t.Errorf("FormatBoard() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package common
+
+import "fmt"
+
+// Handicap is a named preset that gives corners to the weaker player at the start of a game. The
+// zero value is no handicap.
+type Handicap string
+
+const (
+	NoHandicap   = Handicap("")
+	OneCorner    = Handicap("1corner")
+	TwoCorners   = Handicap("2corners")
+	ThreeCorners = Handicap("3corners")
+	FourCorners  = Handicap("4corners")
+)
+
+// Handicaps are the handicaps that a game can be started with, from weakest to strongest.
+var Handicaps = []Handicap{NoHandicap, OneCorner, TwoCorners, ThreeCorners, FourCorners}
+
+// ValidHandicap returns true if handicap is one of Handicaps.
+func ValidHandicap(handicap Handicap) bool {
+	for _, h := range Handicaps {
+		if h == handicap {
+			return true
+		}
+	}
+	return false
+}
+
+// Corners returns the number of corners that the handicap gives.
+func (h Handicap) Corners() int {
+	for i, handicap := range Handicaps {
+		if handicap == h {
+			return i
+		}
+	}
+	return 0
+}
+
+// Squares returns the corners that the handicap gives on a board of the given size. Corners are
+// given in the traditional order: top-left, bottom-right, top-right, bottom-left.
+func (h Handicap) Squares(size int) [][2]int {
+	last := size - 1
+	corners := [][2]int{{0, 0}, {last, last}, {last, 0}, {0, last}}
+	return corners[:h.Corners()]
+}
+
+// Apply returns a copy of the board with the player's disks placed on the corners of the handicap,
+// replacing whatever was on them.
+func (h Handicap) Apply(board Board, player Disk) Board {
+	for _, corner := range h.Squares(board.Size()) {
+		board.Set(corner[0], corner[1], player)
+	}
+
+	return board
+}
+
+func (h Handicap) String() string {
+	switch n := h.Corners(); n {
+	case 0:
+		return "no handicap"
+	case 1:
+		return "1 corner handicap"
+	default:
+		return fmt.Sprintf("%d corners handicap", n)
+	}
+}
@@ -0,0 +1,48 @@
+package common_test
+
+import (
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestHandicapApply(t *testing.T) {
+	tests := []struct {
+		handicap Handicap
+		corners  []move
+	}{
+		{handicap: NoHandicap},
+		{handicap: OneCorner, corners: []move{{0, 0}}},
+		{handicap: TwoCorners, corners: []move{{0, 0}, {7, 7}}},
+		{handicap: ThreeCorners, corners: []move{{0, 0}, {7, 7}, {7, 0}}},
+		{handicap: FourCorners, corners: []move{{0, 0}, {7, 7}, {7, 0}, {0, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.handicap.String(), func(t *testing.T) {
+			got := tt.handicap.Apply(NewMatch(DefaultBoardSize).Board, Player2)
+
+			want := buildTestBoard(
+				[]move{{3, 3}, {4, 4}},
+				append([]move{{3, 4}, {4, 3}}, tt.corners...),
+			)
+
+			if got != want {
+				t.Errorf("Apply() got %v, want %v", got, want)
+			}
+
+			squares := tt.handicap.Squares(DefaultBoardSize)
+			if len(squares) != len(tt.corners) {
+				t.Errorf("Squares() got %v, want %v", squares, tt.corners)
+			}
+			for i, square := range squares {
+				if i < len(tt.corners) && square != [2]int(tt.corners[i]) {
+					t.Errorf("Squares() got %v, want %v", squares, tt.corners)
+				}
+			}
+
+			if err := ValidateStart(got, Player1); err != nil {
+				t.Errorf("ValidateStart() got error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrGameOver    = errors.New("the game is over")
+	ErrIllegalMove = errors.New("illegal move")
+)
+
+// Reasons that a Match can end.
+const (
+	ReasonBoardFull = "board full"
+	ReasonNoMoves   = "no legal moves"
+)
+
+// Turn is a single entry in the history of a Match. It is either a disk placed by the player, or a
+// pass if the player had no legal moves.
+type Turn struct {
+	Player Disk
+	X      int
+	Y      int
+	Pass   bool
+}
+
+// Result is the final outcome of a Match.
+type Result struct {
+	// Winner is the player who won according to the Variant of the Match, or zero if the game is
+	// a draw.
+	Winner Disk
+
+	// Scores are the number of disks that each player has, where the score of player p is at
+	// index p-1.
+	Scores []int
+
+	Reason string
+}
+
+// Match is the referee of a game. It owns the board, whose turn it is, the history of the game,
+// and the final result. It should be used instead of calling ApplyMove directly whenever the rules
+// of turn order matter.
+//
+// Players is zero in matches that were saved before games could have more than two players, which
+// are two-player matches.
+type Match struct {
+	Start       Board
+	StartPlayer Disk
+	Variant     Variant `json:",omitempty"`
+	Players     int
+	Board       Board
+	Player      Disk
+	History     []Turn
+	Result      *Result `json:",omitempty"`
+}
+
+// NewMatch returns a two-player Match on a board of the given size, with the standard opening
+// position of 4 disks in the center, where player 1 moves first.
+func NewMatch(size int) Match {
+	return NewMatchForPlayers(size, 2)
+}
+
+// NewMatchForPlayers returns a Match for 2 to MaxPlayers players on a board of the given size,
+// starting from StartingBoard, where player 1 moves first.
+func NewMatchForPlayers(size, players int) Match {
+	return NewMatchFromPosition(StartingBoard(size, players), Player1)
+}
+
+// StartingBoard returns the opening position of a game for 2 to MaxPlayers players. The disks fill
+// a square in the center of the board that is as wide as the number of players, and each row of
+// the square is the one above it shifted by one, so that every player starts with moves. For two
+// players this is the standard opening position.
+func StartingBoard(size, players int) Board {
+	if players < 2 || players > MaxPlayers {
+		panic(fmt.Errorf("common: invalid number of players %d", players))
+	}
+
+	board := NewBoard(size)
+	offset := (size - players) / 2
+
+	for i := 0; i < players; i++ {
+		for j := 0; j < players; j++ {
+			board.Set(offset+i, offset+j, Disk((i+j)%players+1))
+		}
+	}
+
+	return board
+}
+
+// NewMatchFromPosition returns a Match that starts from an arbitrary position. The number of
+// players is the PlayerCount of the board. If the player has no legal moves then the next player
+// who can move goes first, and if nobody can move then the Match is already over.
+func NewMatchFromPosition(board Board, player Disk) Match {
+	m := Match{
+		Start:       board,
+		StartPlayer: player,
+		Players:     PlayerCount(board),
+		Board:       board,
+		Player:      player,
+	}
+
+	m.advance()
+
+	return m
+}
+
+// ValidateStart returns an error if a Match cannot start from the board with the player to move,
+// such as if the board has an unsupported size or the player has no legal moves.
+func ValidateStart(board Board, player Disk) error {
+	if !ValidBoardSize(board.Size()) {
+		return fmt.Errorf("unsupported board size %d", board.Size())
+	}
+
+	if player < Player1 || player > MaxPlayers {
+		return fmt.Errorf("invalid player %d", player)
+	}
+
+	for x := 0; x < board.Size(); x++ {
+		for y := 0; y < board.Size(); y++ {
+			if disk := board.At(x, y); disk > Blocked {
+				return fmt.Errorf("invalid disk %d at (%d, %d)", disk, x, y)
+			}
+		}
+	}
+
+	if !HasMoves(board, player) {
+		return fmt.Errorf("player %d has no legal moves", player)
+	}
+
+	return nil
+}
+
+// Play places a disk at (x, y) for the player whose turn it is, and then passes the turn to the
+// next player who is able to move.
+func (m *Match) Play(x int, y int) error {
+	if m.IsOver() {
+		return ErrGameOver
+	}
+
+	board, updated := ApplyMove(m.Board, x, y, m.Player)
+	if !updated {
+		return fmt.Errorf("%w: player %d at (%d, %d)", ErrIllegalMove, m.Player, x, y)
+	}
+
+	player := m.Player
+
+	m.Board = board
+	m.History = append(m.History, Turn{Player: player, X: x, Y: y})
+	m.Player = NextPlayer(player, m.playerCount())
+
+	m.advance()
+
+	if m.IsOver() {
+		// The turn stays with whoever made the last move, so that clients can still see who that
+		// was.
+		m.Player = player
+	}
+
+	return nil
+}
+
+// LegalMoves returns the legal moves of the player whose turn it is.
+func (m *Match) LegalMoves() []Move {
+	if m.IsOver() {
+		return nil
+	}
+	return LegalMoves(m.Board, m.Player)
+}
+
+// advance passes the turn on from each player who has no legal moves, recording a pass for them,
+// until it reaches a player who can move. If nobody can move then the game is over.
+func (m *Match) advance() {
+	var passed []Disk
+	player := m.Player
+
+	for i := 0; i < m.playerCount(); i++ {
+		if HasMoves(m.Board, player) {
+			if len(m.History) > 0 {
+				for _, p := range passed {
+					m.History = append(m.History, Turn{Player: p, Pass: true})
+				}
+			}
+			m.Player = player
+			return
+		}
+
+		passed = append(passed, player)
+		player = NextPlayer(player, m.playerCount())
+	}
+
+	// Nobody can move, so the game is over.
+	scores := Scores(m.Board, m.playerCount())
+	m.Result = &Result{Winner: m.Variant.Winner(scores), Scores: scores, Reason: ReasonNoMoves}
+
+	if !m.Board.hasEmpty() {
+		m.Result.Reason = ReasonBoardFull
+	}
+}
+
+// SetVariant changes the rules that decide who wins the Match. If the Match is already over, the
+// winner is decided again.
+func (m *Match) SetVariant(variant Variant) {
+	m.Variant = variant
+
+	if m.Result != nil {
+		m.Result.Winner = variant.Winner(m.Result.Scores)
+	}
+}
+
+// Passed returns true if the previous player had no legal moves and so had to pass. In a
+// two-player game, this means that the current player is moving twice in a row.
+func (m *Match) Passed() bool {
+	return len(m.History) > 0 && m.History[len(m.History)-1].Pass
+}
+
+// Undo takes back the last disk that was placed, along with any pass that followed it. It returns
+// false and leaves the Match unchanged if there is nothing to undo, or if the history cannot be
+// replayed from the start of the Match.
+func (m *Match) Undo() bool {
+	n := len(m.History)
+	for n > 0 && m.History[n-1].Pass {
+		n--
+	}
+	if n == 0 {
+		return false
+	}
+
+	replay := m.Restart()
+
+	for _, turn := range m.History[:n-1] {
+		if turn.Pass {
+			continue
+		}
+		if err := replay.Play(turn.X, turn.Y); err != nil {
+			return false
+		}
+	}
+
+	*m = replay
+	return true
+}
+
+// Restart returns a new Match in the starting position of the Match, with the same Variant and
+// none of its history. Matches that were saved without a starting position started from the
+// standard opening, with player 1 to move.
+func (m *Match) Restart() Match {
+	start, player := m.Start, m.StartPlayer
+	if (start == Board{}) {
+		start, player = StartingBoard(m.Board.Size(), m.playerCount()), Player1
+	}
+
+	restarted := NewMatchFromPosition(start, player)
+	restarted.SetVariant(m.Variant)
+
+	return restarted
+}
+
+// IsOver returns true if nobody can move.
+func (m *Match) IsOver() bool {
+	return m.Result != nil
+}
+
+// Scores returns the number of disks that each player has on the board, where the score of player
+// p is at index p-1.
+func (m *Match) Scores() []int {
+	return Scores(m.Board, m.playerCount())
+}
+
+// playerCount returns the number of players of the Match, treating a Match that was saved without
+// it as a two-player Match.
+func (m *Match) playerCount() int {
+	if m.Players == 0 {
+		return 2
+	}
+	return m.Players
+}
@@ -0,0 +1,360 @@
+package common_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestMatchPlay(t *testing.T) {
+	m := NewMatch(DefaultBoardSize)
+
+	if err := m.Play(2, 4); err != nil {
+		t.Fatalf("Play() got error %v", err)
+	}
+
+	if m.Player != Player2 {
+		t.Errorf("Play() got player %d, want %d", m.Player, Player2)
+	}
+
+	if err := m.Play(0, 0); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Play() got error %v, want %v", err, ErrIllegalMove)
+	}
+
+	if scores := m.Scores(); !reflect.DeepEqual(scores, []int{4, 1}) {
+		t.Errorf("Scores() got %v, want [4 1]", scores)
+	}
+}
+
+func TestMatchPass(t *testing.T) {
+	// Player 2 has no moves after player 1 plays (0, 2), so player 1 goes again.
+	board := buildTestBoard(
+		[]move{{0, 0}, {0, 7}, {1, 7}, {2, 7}, {3, 7}, {4, 7}, {5, 7}},
+		[]move{{0, 1}, {6, 7}},
+	)
+
+	m := NewMatchFromPosition(board, Player1)
+
+	if err := m.Play(0, 2); err != nil {
+		t.Fatalf("Play() got error %v", err)
+	}
+
+	if !m.Passed() {
+		t.Errorf("Passed() got false, want true")
+	}
+
+	if m.Player != Player1 {
+		t.Errorf("Play() got player %d, want %d", m.Player, Player1)
+	}
+
+	want := []Turn{{Player: Player1, X: 0, Y: 2}, {Player: Player2, Pass: true}}
+	if len(m.History) != len(want) || m.History[0] != want[0] || m.History[1] != want[1] {
+		t.Errorf("History got %v, want %v", m.History, want)
+	}
+}
+
+func TestMatchGameOver(t *testing.T) {
+	board := buildTestBoard(
+		[]move{{0, 0}},
+		[]move{{0, 1}},
+	)
+
+	m := NewMatchFromPosition(board, Player1)
+
+	if err := m.Play(0, 2); err != nil {
+		t.Fatalf("Play() got error %v", err)
+	}
+
+	if !m.IsOver() {
+		t.Fatalf("IsOver() got false, want true")
+	}
+
+	want := Result{Winner: Player1, Scores: []int{3, 0}, Reason: ReasonNoMoves}
+	if !reflect.DeepEqual(*m.Result, want) {
+		t.Errorf("Result got %+v, want %+v", *m.Result, want)
+	}
+
+	if m.Player != Player1 {
+		t.Errorf("Player got %d, want the last player to move (%d)", m.Player, Player1)
+	}
+
+	if err := m.Play(5, 5); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Play() got error %v, want %v", err, ErrGameOver)
+	}
+}
+
+func TestMatchUndo(t *testing.T) {
+	m := NewMatch(DefaultBoardSize)
+	start := m.Board
+
+	if m.Undo() {
+		t.Errorf("Undo() got true on a new match, want false")
+	}
+
+	for _, move := range []move{{2, 4}, {2, 5}} {
+		if err := m.Play(move[0], move[1]); err != nil {
+			t.Fatalf("Play() got error %v", err)
+		}
+	}
+
+	if !m.Undo() {
+		t.Fatalf("Undo() got false, want true")
+	}
+
+	want := NewMatch(DefaultBoardSize)
+	_ = want.Play(2, 4)
+	if m.Board != want.Board || m.Player != Player2 || len(m.History) != 1 {
+		t.Errorf("Undo() got board %v with player %d, want %v with player %d", m.Board, m.Player, want.Board, Player2)
+	}
+
+	if !m.Undo() || m.Board != start {
+		t.Errorf("Undo() got board %v, want %v", m.Board, start)
+	}
+}
+
+func TestMatchAntiOthello(t *testing.T) {
+	board := buildTestBoard(
+		[]move{{0, 0}},
+		[]move{{0, 1}},
+	)
+
+	m := NewMatchFromPosition(board, Player1)
+	m.SetVariant(AntiOthello)
+
+	if err := m.Play(0, 2); err != nil {
+		t.Fatalf("Play() got error %v", err)
+	}
+
+	want := Result{Winner: Player2, Scores: []int{3, 0}, Reason: ReasonNoMoves}
+	if !reflect.DeepEqual(*m.Result, want) {
+		t.Errorf("Result got %+v, want %+v", *m.Result, want)
+	}
+
+	m.SetVariant(Standard)
+
+	if m.Result.Winner != Player1 {
+		t.Errorf("SetVariant() got winner %d, want %d", m.Result.Winner, Player1)
+	}
+
+	m.SetVariant(AntiOthello)
+
+	if !m.Undo() || m.Variant != AntiOthello {
+		t.Errorf("Undo() got variant %q, want %q", m.Variant, AntiOthello)
+	}
+}
+
+func TestValidateStart(t *testing.T) {
+	invalidDisk := NewMatch(DefaultBoardSize).Board
+	invalidDisk.Set(0, 0, Blocked+1)
+
+	tests := []struct {
+		name    string
+		board   Board
+		player  Disk
+		wantErr bool
+	}{
+		{name: "standard", board: NewMatch(DefaultBoardSize).Board, player: Player1},
+		{name: "second player", board: NewMatch(6).Board, player: Player2},
+		{name: "no board", board: Board{}, player: Player1, wantErr: true},
+		{name: "invalid player", board: NewMatch(DefaultBoardSize).Board, player: 3, wantErr: true},
+		{name: "invalid disk", board: invalidDisk, player: Player1, wantErr: true},
+		{name: "no moves", board: buildTestBoard([]move{{0, 0}}, []move{{7, 7}}), player: Player1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateStart(tt.board, tt.player); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStart() got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStartingBoard(t *testing.T) {
+	for players := 2; players <= MaxPlayers; players++ {
+		for _, size := range BoardSizes {
+			board := StartingBoard(size, players)
+
+			if got := PlayerCount(board); got != players {
+				t.Errorf("StartingBoard(%d, %d) got %d players", size, players, got)
+			}
+
+			want := make([]int, players)
+			for i := range want {
+				want[i] = players
+			}
+			if got := Scores(board, players); !reflect.DeepEqual(got, want) {
+				t.Errorf("StartingBoard(%d, %d) got scores %v, want %v", size, players, got, want)
+			}
+
+			for player := Player1; player <= Disk(players); player++ {
+				if err := ValidateStart(board, player); err != nil {
+					t.Errorf("StartingBoard(%d, %d) for player %d got error %v", size, players, player, err)
+				}
+			}
+		}
+	}
+}
+
+// playRandomMoves plays up to n random legal moves in the match, stopping early if the game ends.
+func playRandomMoves(tb testing.TB, rng *rand.Rand, m *Match, n int) {
+	tb.Helper()
+
+	for i := 0; i < n && !m.IsOver(); i++ {
+		moves := m.LegalMoves()
+		move := moves[rng.Intn(len(moves))]
+		if err := m.Play(move.X, move.Y); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func TestMatchTurnRotation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for players := 2; players <= MaxPlayers; players++ {
+		passes := 0
+
+		for game := 0; game < 20; game++ {
+			m := NewMatchForPlayers(10, players)
+			playRandomMoves(t, rng, &m, m.Board.Empties())
+
+			// Every player takes a turn in order, and players who cannot move pass.
+			for i := 1; i < len(m.History); i++ {
+				if want := NextPlayer(m.History[i-1].Player, players); m.History[i].Player != want {
+					t.Fatalf("History got player %d after player %d, want %d", m.History[i].Player, m.History[i-1].Player, want)
+				}
+				if m.History[i].Pass {
+					passes++
+				}
+			}
+
+			scores := m.Scores()
+			if !reflect.DeepEqual(m.Result.Scores, scores) {
+				t.Errorf("Result got scores %v, want %v", m.Result.Scores, scores)
+			}
+			if winner := m.Result.Winner; winner != 0 {
+				for _, score := range scores {
+					if score > scores[winner-1] {
+						t.Errorf("Result got winner %d with scores %v", winner, scores)
+					}
+				}
+			}
+		}
+
+		if passes == 0 {
+			t.Errorf("no player passed in any game of %d players", players)
+		}
+	}
+}
+
+// savedWithout returns the match after saving it as JSON without the given fields, like a match
+// that was saved before the fields existed.
+func savedWithout(t *testing.T, m Match, fields ...string) Match {
+	t.Helper()
+
+	data, err := json.Marshal(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range fields {
+		delete(object, field)
+	}
+	if data, err = json.Marshal(object); err != nil {
+		t.Fatal(err)
+	}
+
+	var saved Match
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func TestMatchWithoutPlayers(t *testing.T) {
+	// A match that was saved before it recorded the number of players.
+	saved := savedWithout(t, NewMatch(DefaultBoardSize), "Players")
+
+	if err := saved.Play(2, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if saved.Player != Player2 {
+		t.Errorf("Play() got player %d to move, want %d", saved.Player, Player2)
+	}
+
+	if scores := saved.Scores(); !reflect.DeepEqual(scores, []int{4, 1}) {
+		t.Errorf("Scores() got %v, want [4 1]", scores)
+	}
+}
+
+func TestMatchWithoutStart(t *testing.T) {
+	m := NewMatch(DefaultBoardSize)
+	m.SetVariant(AntiOthello)
+	if err := m.PlayTranscript("f5d6c3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A match that was saved before it recorded its starting position.
+	saved := savedWithout(t, m, "Start", "StartPlayer", "Players")
+
+	restarted := saved.Restart()
+	if restarted.Board != NewMatch(DefaultBoardSize).Board || restarted.Player != Player1 || len(restarted.History) != 0 || restarted.Variant != AntiOthello {
+		t.Errorf("Restart() got board %v with player %d, want the standard opening", restarted.Board, restarted.Player)
+	}
+
+	if !saved.Undo() {
+		t.Fatal("Undo() got false, want true")
+	}
+
+	want := NewMatch(DefaultBoardSize)
+	_ = want.PlayTranscript("f5d6")
+	if saved.Board != want.Board || saved.Player != want.Player {
+		t.Errorf("Undo() got board %v with player %d, want %v with player %d", saved.Board, saved.Player, want.Board, want.Player)
+	}
+}
+
+func TestMatchUndoBrokenHistory(t *testing.T) {
+	m := NewMatch(DefaultBoardSize)
+	_ = m.PlayTranscript("f5d6c3")
+
+	// The first move is not legal from the starting position, so the history cannot be replayed.
+	m.History[0].X, m.History[0].Y = 0, 0
+	before := m.Board
+
+	if m.Undo() {
+		t.Error("Undo() of a history that cannot be replayed got true, want false")
+	}
+	if m.Board != before || len(m.History) != 3 {
+		t.Errorf("Undo() of a history that cannot be replayed changed the match to %v", m.Board)
+	}
+}
+
+func TestVariantWinner(t *testing.T) {
+	tests := []struct {
+		scores   []int
+		standard Disk
+		anti     Disk
+	}{
+		{scores: []int{10, 5}, standard: Player1, anti: Player2},
+		{scores: []int{5, 5}},
+		{scores: []int{3, 9, 4}, standard: Player2, anti: Player1},
+		{scores: []int{9, 9, 4}, anti: Player3},
+		{scores: []int{1, 2, 3, 4}, standard: Player4, anti: Player1},
+	}
+	for _, tt := range tests {
+		if got := Standard.Winner(tt.scores); got != tt.standard {
+			t.Errorf("Standard.Winner(%v) got %d, want %d", tt.scores, got, tt.standard)
+		}
+		if got := AntiOthello.Winner(tt.scores); got != tt.anti {
+			t.Errorf("AntiOthello.Winner(%v) got %d, want %d", tt.scores, got, tt.anti)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package common
+
+import "math/rand"
+
+// MaxObstacles is the most obstacles that can be seeded on a new board.
+const MaxObstacles = 8
+
+// SeedObstacles returns a copy of the board with up to n Blocked squares placed at random. The
+// obstacles are only placed on empty squares that are not next to a disk, so that they do not
+// change the opening moves of the game.
+func SeedObstacles(board Board, n int, rng *rand.Rand) Board {
+	var candidates [][2]int
+
+	for x := 0; x < board.size; x++ {
+		for y := 0; y < board.size; y++ {
+			if board.disks[x][y] == 0 && !board.nextToDisk(x, y) {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+	}
+
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+
+	for _, square := range candidates[:n] {
+		board.set(square[0], square[1], Blocked)
+	}
+
+	return board
+}
+
+// nextToDisk returns true if any square around (x, y) has a player's disk on it.
+func (b Board) nextToDisk(x, y int) bool {
+	for _, v := range vectors {
+		if disk := b.At(x+v[0], y+v[1]); disk != 0 && disk != Blocked {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package common_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestBlockedSquareIsWall(t *testing.T) {
+	// Without the obstacle, player 1 playing at (4, 0) would flip (3, 0), (2, 0) and (1, 0).
+	board := buildTestBoard([]move{{0, 0}}, []move{{1, 0}, {2, 0}, {3, 0}})
+	board.Set(2, 0, Blocked)
+
+	if _, updated := ApplyMove(board, 4, 0, Player1); updated {
+		t.Errorf("ApplyMove() flipped disks across a blocked square")
+	}
+
+	if _, updated := ApplyMove(board, 2, 0, Player1); updated {
+		t.Errorf("ApplyMove() played on a blocked square")
+	}
+
+	position, _ := NewPosition(board)
+	if moves := position.LegalMoves(Player1); moves != 0 {
+		t.Errorf("Position.LegalMoves() got %v, want none", moves.Squares())
+	}
+
+	if position.Board() != board {
+		t.Errorf("Position.Board() got %v, want %v", position.Board(), board)
+	}
+}
+
+func TestSeedObstacles(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, size := range BoardSizes {
+		start := NewMatch(size).Board
+		board := SeedObstacles(start, MaxObstacles, rng)
+
+		blocked := 0
+		for x := 0; x < size; x++ {
+			for y := 0; y < size; y++ {
+				if board.At(x, y) == Blocked {
+					blocked++
+				} else if board.At(x, y) != start.At(x, y) {
+					t.Errorf("SeedObstacles() on a board of size %d changed (%d, %d)", size, x, y)
+				}
+			}
+		}
+
+		if blocked != MaxObstacles {
+			t.Errorf("SeedObstacles() on a board of size %d got %d obstacles, want %d", size, blocked, MaxObstacles)
+		}
+
+		if got, want := len(LegalMoves(board, Player1)), len(LegalMoves(start, Player1)); got != want {
+			t.Errorf("SeedObstacles() on a board of size %d changed the number of opening moves from %d to %d", size, want, got)
+		}
+	}
+}
+
+func TestMatchBoardFullWithObstacles(t *testing.T) {
+	board := NewBoard(6)
+	for x := 0; x < 6; x++ {
+		for y := 0; y < 6; y++ {
+			board.Set(x, y, Player1)
+		}
+	}
+	board.Set(0, 0, Blocked)
+
+	m := NewMatchFromPosition(board, Player1)
+
+	want := Result{Winner: Player1, Scores: []int{35, 0}, Reason: ReasonBoardFull}
+	if m.Result == nil || !reflect.DeepEqual(*m.Result, want) {
+		t.Errorf("Result got %+v, want %+v", m.Result, want)
+	}
+}
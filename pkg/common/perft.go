@@ -0,0 +1,76 @@
+package common
+
+import "math/bits"
+
+// Perft counts the positions that can be reached from the board in exactly depth turns, starting
+// with the given player, which is a standard way to check a move generator against known counts.
+//
+// A pass counts as a turn when the player has no legal moves but someone else does. A position
+// where nobody can move is counted as a single leaf, even if it is reached in fewer than depth
+// turns.
+func Perft(board Board, player Disk, depth int) uint64 {
+	if position, ok := NewPosition(board); ok {
+		return perftPosition(position, player, depth)
+	}
+	return perftBoard(board, player, PlayerCount(board), depth)
+}
+
+func perftPosition(position Position, player Disk, depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+
+	moves := position.LegalMoves(player)
+	opponent := player%2 + 1
+
+	if moves == 0 {
+		if !position.HasMoves(opponent) {
+			return 1
+		}
+		return perftPosition(position, opponent, depth-1)
+	}
+
+	// Every move is a leaf at depth 1, so there is no need to apply them.
+	if depth == 1 {
+		return uint64(moves.Count())
+	}
+
+	var nodes uint64
+	for moves != 0 {
+		i := bits.TrailingZeros64(uint64(moves))
+		moves &= moves - 1
+
+		next, _ := position.ApplyMove(i%bitboardSize, i/bitboardSize, player)
+		nodes += perftPosition(next, opponent, depth-1)
+	}
+
+	return nodes
+}
+
+func perftBoard(board Board, player Disk, players, depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+
+	var nodes uint64
+	for x := 0; x < board.size; x++ {
+		for y := 0; y < board.size; y++ {
+			if next, updated := ApplyMove(board, x, y, player); updated {
+				nodes += perftBoard(next, NextPlayer(player, players), players, depth-1)
+			}
+		}
+	}
+
+	if nodes > 0 {
+		return nodes
+	}
+
+	// The player has to pass, unless nobody else can move either.
+	for next := NextPlayer(player, players); next != player; next = NextPlayer(next, players) {
+		if HasMoves(board, next) {
+			return perftBoard(board, NextPlayer(player, players), players, depth-1)
+		}
+	}
+
+	return 1
+}
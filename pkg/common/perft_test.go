@@ -0,0 +1,74 @@
+package common_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+// perftCounts are the published node counts of the standard opening position, indexed by depth.
+var perftCounts = []uint64{1, 4, 12, 56, 244, 1396, 8200, 55092, 390216, 3005288, 24571284}
+
+func TestPerft(t *testing.T) {
+	maxDepth := len(perftCounts) - 1
+	if testing.Short() {
+		maxDepth = 8
+	}
+
+	board := NewMatch(DefaultBoardSize).Board
+
+	for depth := 0; depth <= maxDepth; depth++ {
+		if got, want := Perft(board, Player1, depth), perftCounts[depth]; got != want {
+			t.Errorf("Perft() at depth %d got %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestPerftLargeBoards(t *testing.T) {
+	// The disks cannot reach the edge of a large board in the first few turns, so the counts are
+	// the same as on the standard board.
+	for _, size := range []int{10, MaxBoardSize} {
+		board := NewMatch(size).Board
+
+		for depth := 0; depth <= 6; depth++ {
+			if got, want := Perft(board, Player1, depth), perftCounts[depth]; got != want {
+				t.Errorf("Perft() on a board of size %d at depth %d got %d, want %d", size, depth, got, want)
+			}
+		}
+	}
+}
+
+func TestPerftGameOver(t *testing.T) {
+	// Nobody can move, so the position is a single leaf at every depth.
+	board := buildTestBoard([]move{{0, 0}}, nil)
+
+	for depth := 0; depth <= 3; depth++ {
+		if got := Perft(board, Player1, depth); got != 1 {
+			t.Errorf("Perft() at depth %d got %d, want 1", depth, got)
+		}
+	}
+}
+
+func TestPerftPass(t *testing.T) {
+	// Player 1 cannot move, but player 2 has two moves, so player 1 passes first.
+	board := buildTestBoard([]move{{1, 0}, {1, 1}}, []move{{0, 0}})
+
+	for depth, want := range []uint64{1, 1, 2} {
+		if got := Perft(board, Player1, depth); got != want {
+			t.Errorf("Perft() at depth %d got %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func BenchmarkPerft(b *testing.B) {
+	for _, size := range []int{DefaultBoardSize, 10} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			board := NewMatch(size).Board
+
+			for i := 0; i < b.N; i++ {
+				Perft(board, Player1, 6)
+			}
+		})
+	}
+}
@@ -1,53 +1,99 @@
 package common
 
-func ApplyMove(board Board, x int, y int, player Disk) (Board, bool) {
-	updated := false
-	vectors := [][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+// Move is a legal move, along with the opponent disks that it flips. The flip count of a move is
+// len(Flips).
+type Move struct {
+	X     int
+	Y     int
+	Flips [][2]int
+}
 
-	if isInBounds(x, y) && board[x][y] != 0 {
+// vectors are the 8 directions along which disks can be flipped.
+var vectors = [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+
+func ApplyMove(board Board, x int, y int, player Disk) (Board, bool) {
+	if !isPlayable(&board, x, y) {
 		return board, false
 	}
 
+	updated := false
+
 	for _, v := range vectors {
-		if flipAlongVector(&board, x+v[0], y+v[1], player, v, 0) {
+		n := flipLength(&board, x, y, player, v)
+		for i := 1; i <= n; i++ {
+			board.set(x+v[0]*i, y+v[1]*i, player)
+		}
+		if n > 0 {
 			updated = true
-			board[x][y] = player
+			board.set(x, y, player)
 		}
 	}
 
 	return board, updated
 }
 
-func flipAlongVector(board *Board, x int, y int, player Disk, v [2]int, depth int) bool {
-	if !isInBounds(x, y) {
-		return false
+// LegalMoves returns every legal move that the player can make, in column-major order.
+func LegalMoves(board Board, player Disk) []Move {
+	var moves []Move
+
+	for x := 0; x < board.size; x++ {
+		for y := 0; y < board.size; y++ {
+			if flips := Flips(board, x, y, player); len(flips) > 0 {
+				moves = append(moves, Move{X: x, Y: y, Flips: flips})
+			}
+		}
 	}
 
-	disk := board[x][y]
+	return moves
+}
 
-	switch disk {
-	case 0:
-		return false
-	case player:
-		return depth > 0
+// Flips returns the coordinates of the opponent disks that would be flipped if the player placed a
+// disk at (x, y). It returns nil if the move is illegal.
+func Flips(board Board, x int, y int, player Disk) [][2]int {
+	if !isPlayable(&board, x, y) {
+		return nil
 	}
 
-	if flipAlongVector(board, x+v[0], y+v[1], player, v, depth+1) {
-		board[x][y] = player
-		return true
+	var flips [][2]int
+
+	for _, v := range vectors {
+		n := flipLength(&board, x, y, player, v)
+		for i := 1; i <= n; i++ {
+			flips = append(flips, [2]int{x + v[0]*i, y + v[1]*i})
+		}
 	}
 
-	return false
+	return flips
+}
+
+// flipLength returns the number of opponent disks that would be flipped along the vector v if the
+// player placed a disk at (x, y).
+func flipLength(board *Board, x int, y int, player Disk, v [2]int) int {
+	for n := 0; ; n++ {
+		x, y = x+v[0], y+v[1]
+
+		if !board.InBounds(x, y) {
+			return 0
+		}
+
+		switch board.disks[x][y] {
+		case 0, Blocked:
+			return 0
+		case player:
+			return n
+		}
+	}
 }
 
-func isInBounds(x int, y int) bool {
-	return x >= 0 && x < BoardSize && y >= 0 && y < BoardSize
+// isPlayable returns true if (x, y) is an empty square on the board.
+func isPlayable(board *Board, x int, y int) bool {
+	return board.InBounds(x, y) && board.disks[x][y] == 0
 }
 
 func KeepScore(board Board) (p1 int, p2 int) {
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			switch board[i][j] {
+	for i := 0; i < board.size; i++ {
+		for j := 0; j < board.size; j++ {
+			switch board.disks[i][j] {
 			case 1:
 				p1++
 			case 2:
@@ -59,18 +105,70 @@ func KeepScore(board Board) (p1 int, p2 int) {
 	return p1, p2
 }
 
+// Scores returns the number of disks that each player has on the board in a game of the given
+// number of players. The score of player p is at index p-1.
+func Scores(board Board, players int) []int {
+	scores := make([]int, players)
+
+	for i := 0; i < board.size; i++ {
+		for j := 0; j < board.size; j++ {
+			if disk := board.disks[i][j]; disk != 0 && int(disk) <= players {
+				scores[disk-1]++
+			}
+		}
+	}
+
+	return scores
+}
+
+// PlayerCount returns the number of players in a game on the board, which is the highest numbered
+// player who has a disk on it, and never less than two. A player who has lost all of their disks
+// can never move again, so they do not need to be counted.
+func PlayerCount(board Board) int {
+	players := 2
+
+	for i := 0; i < board.size; i++ {
+		for j := 0; j < board.size; j++ {
+			if disk := board.disks[i][j]; disk != Blocked && int(disk) > players {
+				players = int(disk)
+			}
+		}
+	}
+
+	return players
+}
+
+// NextPlayer returns the player who takes their turn after the given player, in a game of the
+// given number of players.
+func NextPlayer(player Disk, players int) Disk {
+	return player%Disk(players) + 1
+}
+
+// GameOver returns true if none of the players on the board can move.
 func GameOver(board Board) bool {
 	if (board == Board{}) {
 		return false
 	}
-	return !(HasMoves(board, 1) || HasMoves(board, 2))
+
+	for player, players := Player1, Disk(PlayerCount(board)); player <= players; player++ {
+		if HasMoves(board, player) {
+			return false
+		}
+	}
+
+	return true
 }
 
 func HasMoves(board Board, player Disk) bool {
-	for i := 0; i < BoardSize; i++ {
-		for j := 0; j < BoardSize; j++ {
-			if _, updated := ApplyMove(board, i, j, player); updated {
-				return true
+	for i := 0; i < board.size; i++ {
+		for j := 0; j < board.size; j++ {
+			if !isPlayable(&board, i, j) {
+				continue
+			}
+			for _, v := range vectors {
+				if flipLength(&board, i, j, player, v) > 0 {
+					return true
+				}
 			}
 		}
 	}
@@ -1,6 +1,8 @@
 package common_test
 
 import (
+	"math/rand"
+	"reflect"
 	"testing"
 
 	. "github.com/armsnyder/othelgo/pkg/common"
@@ -8,12 +10,17 @@ import (
 
 type move [2]int
 
-func buildTestBoard(p1, p2 []move) (board Board) {
+func buildTestBoard(p1, p2 []move) Board {
+	return buildSizedTestBoard(DefaultBoardSize, p1, p2)
+}
+
+func buildSizedTestBoard(size int, p1, p2 []move) Board {
+	board := NewBoard(size)
 	for i, moves := range [][]move{p1, p2} {
 		player := i + 1
 		for _, move := range moves {
 			x, y := move[0], move[1]
-			board[x][y] = Disk(player)
+			board.Set(x, y, Disk(player))
 		}
 	}
 	return board
@@ -202,6 +209,112 @@ func TestApplyMove(t *testing.T) {
 				t.Errorf("ApplyMove() got updated = %v, want %v", gotUpdated, !tt.wantNotUpdated)
 			}
 		})
+
+		t.Run(tt.name+" bitboard", func(t *testing.T) {
+			if tt.wantNotUpdated {
+				tt.wantBoard = tt.args.board
+			}
+			position, _ := NewPosition(tt.args.board)
+			gotPosition, gotUpdated := position.ApplyMove(tt.args.x, tt.args.y, tt.args.player)
+			if gotBoard := gotPosition.Board(); gotBoard != tt.wantBoard {
+				t.Errorf("Position.ApplyMove() got board = %v, want %v", gotBoard, tt.wantBoard)
+			}
+			if gotUpdated == tt.wantNotUpdated {
+				t.Errorf("Position.ApplyMove() got updated = %v, want %v", gotUpdated, !tt.wantNotUpdated)
+			}
+		})
+	}
+}
+
+func TestLegalMoves(t *testing.T) {
+	board := buildTestBoard([]move{{3, 3}, {4, 4}}, []move{{3, 4}, {4, 3}})
+
+	got := LegalMoves(board, Player1)
+
+	want := []Move{
+		{X: 2, Y: 4, Flips: [][2]int{{3, 4}}},
+		{X: 3, Y: 5, Flips: [][2]int{{3, 4}}},
+		{X: 4, Y: 2, Flips: [][2]int{{4, 3}}},
+		{X: 5, Y: 3, Flips: [][2]int{{4, 3}}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LegalMoves() got %v, want %v", got, want)
+	}
+}
+
+func TestFlips(t *testing.T) {
+	board := buildTestBoard(
+		[]move{{1, 1}, {1, 3}, {1, 5}, {3, 5}, {5, 5}, {5, 3}, {5, 1}, {3, 1}},
+		[]move{{2, 2}, {2, 3}, {2, 4}, {3, 4}, {4, 4}, {4, 3}, {4, 2}, {3, 2}},
+	)
+
+	if got := Flips(board, 3, 3, Player1); len(got) != 8 {
+		t.Errorf("Flips() got %v, want 8 flips", got)
+	}
+
+	if got := Flips(board, 3, 3, Player2); got != nil {
+		t.Errorf("Flips() got %v, want nil", got)
+	}
+
+	if got := Flips(board, 2, 2, Player1); got != nil {
+		t.Errorf("Flips() on an occupied square got %v, want nil", got)
+	}
+}
+
+func TestPositionMatchesBoard(t *testing.T) {
+	// Play random games using both representations and check that they never disagree.
+	rng := rand.New(rand.NewSource(1))
+
+	for game := 0; game < 50; game++ {
+		board := buildTestBoard([]move{{3, 3}, {4, 4}}, []move{{3, 4}, {4, 3}})
+		if game%2 == 1 {
+			board = SeedObstacles(board, MaxObstacles, rng)
+		}
+		position, _ := NewPosition(board)
+		player := Player1
+
+		for !GameOver(board) {
+			legal := LegalMoves(board, player)
+
+			var wantMoves Bitboard
+			for _, m := range legal {
+				wantMoves |= SquareBit(m.X, m.Y)
+
+				if got := position.Flips(m.X, m.Y, player).Count(); got != len(m.Flips) {
+					t.Fatalf("Position.Flips() got %d flips, want %v on board %v", got, m.Flips, board)
+				}
+			}
+
+			if got := position.LegalMoves(player); got != wantMoves {
+				t.Fatalf("Position.LegalMoves() got %v, want %v on board %v", got.Squares(), legal, board)
+			}
+
+			if len(legal) > 0 {
+				m := legal[rng.Intn(len(legal))]
+				board, _ = ApplyMove(board, m.X, m.Y, player)
+				position, _ = position.ApplyMove(m.X, m.Y, player)
+
+				if position.Board() != board {
+					t.Fatalf("Position.ApplyMove() got board = %v, want %v", position.Board(), board)
+				}
+
+				if position.Key(player) != board.Key(player) {
+					t.Fatalf("Position.Key() got %x, want %x on board %v", position.Key(player), board.Key(player), board)
+				}
+			}
+
+			player = player%2 + 1
+		}
+
+		p1, p2 := KeepScore(board)
+		if gotP1, gotP2 := position.Score(); gotP1 != p1 || gotP2 != p2 {
+			t.Errorf("Position.Score() got %d-%d, want %d-%d", gotP1, gotP2, p1, p2)
+		}
+
+		if !position.GameOver() {
+			t.Errorf("Position.GameOver() got false, want true on board %v", board)
+		}
 	}
 }
 
@@ -256,12 +369,47 @@ func BenchmarkApplyMove(b *testing.B) {
 		},
 	}
 	for _, tt := range tests {
-		b.Run(tt.name, func(b *testing.B) {
+		b.Run(tt.name+" board", func(b *testing.B) {
 			b.ReportAllocs()
 
 			for i := 0; i < b.N; i++ {
 				ApplyMove(tt.args.board, tt.args.x, tt.args.y, tt.args.player)
 			}
 		})
+
+		b.Run(tt.name+" bitboard", func(b *testing.B) {
+			b.ReportAllocs()
+
+			position, _ := NewPosition(tt.args.board)
+
+			for i := 0; i < b.N; i++ {
+				position.ApplyMove(tt.args.x, tt.args.y, tt.args.player)
+			}
+		})
 	}
 }
+
+func BenchmarkHasMoves(b *testing.B) {
+	board := buildTestBoard(
+		[]move{{3, 3}, {4, 4}, {2, 4}, {3, 4}},
+		[]move{{4, 3}},
+	)
+
+	b.Run("board", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			HasMoves(board, Player2)
+		}
+	})
+
+	b.Run("bitboard", func(b *testing.B) {
+		b.ReportAllocs()
+
+		position, _ := NewPosition(board)
+
+		for i := 0; i < b.N; i++ {
+			position.HasMoves(Player2)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// ErrTooManyPlayers is returned by Solve for a game of more than two players.
+var ErrTooManyPlayers = errors.New("only two-player games can be solved")
+
+// Solution is the outcome of a game when both players play perfectly from a position.
+type Solution struct {
+	// X and Y are the coordinates of a best move for the player to move, or -1 if the player has
+	// no legal moves.
+	X, Y int
+
+	// Differential is the number of disks that the player to move will have at the end of the
+	// game, minus the number of disks that their opponent will have.
+	Differential int
+
+	// Winner is the player who wins, or zero if the game is a draw.
+	Winner Disk
+}
+
+// Solve searches every possible continuation of a two-player game to find its exact outcome with
+// perfect play, according to the rules of the variant. The time it takes grows exponentially with
+// the number of empty squares, so it is only practical near the end of a game. Around 14 empty
+// squares on the default board, and fewer on other boards, can be solved in about a second. If the
+// context is done before the search finishes, Solve gives up and returns the context's error.
+func Solve(ctx context.Context, board Board, player Disk, variant Variant) (Solution, error) {
+	if PlayerCount(board) > 2 {
+		return Solution{}, ErrTooManyPlayers
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Solution{}, err
+	}
+
+	s := &solver{ctx: ctx, sign: 1}
+	if variant == AntiOthello {
+		s.sign = -1
+	}
+
+	var (
+		value int
+		move  = [2]int{-1, -1}
+	)
+
+	if position, ok := NewPosition(board); ok {
+		value, move = s.positionRoot(position, player)
+	} else {
+		value, move = s.boardRoot(board, player)
+	}
+
+	if s.stopped {
+		return Solution{}, ctx.Err()
+	}
+
+	solution := Solution{X: move[0], Y: move[1], Differential: s.sign * value}
+
+	switch {
+	case value > 0:
+		solution.Winner = player
+	case value < 0:
+		solution.Winner = player%2 + 1
+	}
+
+	return solution, nil
+}
+
+// Solve finds the exact outcome of the Match from its current position with perfect play by both
+// players, which answers who wins from here. It returns ErrGameOver if the game is already over.
+func (m *Match) Solve(ctx context.Context) (Solution, error) {
+	if m.IsOver() {
+		return Solution{}, ErrGameOver
+	}
+	return Solve(ctx, m.Board, m.Player, m.Variant)
+}
+
+// The solver uses negamax with alpha-beta pruning. The value of a position is the final disk
+// differential from the point of view of the player to move, multiplied by the sign of the
+// variant, so that a higher value is always better for the player to move.
+
+// solveOrderingEmpties is the number of empty squares above which the solver sorts moves so that
+// the ones that leave the opponent the fewest replies are searched first. Sorting costs more than
+// it saves close to the end of the game.
+const solveOrderingEmpties = 7
+
+// solveCheckInterval is the number of positions that the solver looks at between checks of
+// whether its context is done.
+const solveCheckInterval = 4096
+
+// solver is a single search for the exact outcome of a game, which stops as soon as its context is
+// done. The values that it returns after it stops are meaningless.
+type solver struct {
+	ctx     context.Context
+	sign    int
+	nodes   int
+	stopped bool
+}
+
+// visit counts a position, and returns false if the search should stop.
+func (s *solver) visit() bool {
+	s.nodes++
+	if s.nodes%solveCheckInterval == 0 && s.ctx.Err() != nil {
+		s.stopped = true
+	}
+	return !s.stopped
+}
+
+func (s *solver) positionRoot(position Position, player Disk) (int, [2]int) {
+	moves := position.LegalMoves(player)
+	if moves == 0 {
+		return s.position(position, player, -bitboardSize*bitboardSize, bitboardSize*bitboardSize, false), [2]int{-1, -1}
+	}
+
+	best, bestMove := -bitboardSize*bitboardSize-1, [2]int{-1, -1}
+	opponent := player%2 + 1
+
+	for _, child := range orderedChildren(position, player, moves) {
+		value := -s.position(child.position, opponent, -bitboardSize*bitboardSize, -best, false)
+		if s.stopped {
+			break
+		}
+		if value > best {
+			best, bestMove = value, child.move
+		}
+	}
+
+	return best, bestMove
+}
+
+func (s *solver) position(position Position, player Disk, alpha, beta int, passed bool) int {
+	if !s.visit() {
+		return 0
+	}
+
+	moves := position.LegalMoves(player)
+	opponent := player%2 + 1
+
+	if moves == 0 {
+		if passed {
+			p1, p2 := position.Score()
+			if player == Player2 {
+				p1, p2 = p2, p1
+			}
+			return s.sign * (p1 - p2)
+		}
+		return -s.position(position, opponent, -beta, -alpha, true)
+	}
+
+	if position.Empty().Count() > solveOrderingEmpties {
+		for _, child := range orderedChildren(position, player, moves) {
+			if value := -s.position(child.position, opponent, -beta, -alpha, false); value > alpha {
+				alpha = value
+				if alpha >= beta {
+					break
+				}
+			}
+		}
+		return alpha
+	}
+
+	for moves != 0 {
+		i := bits.TrailingZeros64(uint64(moves))
+		moves &= moves - 1
+
+		child, _ := position.ApplyMove(i%bitboardSize, i/bitboardSize, player)
+		if value := -s.position(child, opponent, -beta, -alpha, false); value > alpha {
+			alpha = value
+			if alpha >= beta {
+				break
+			}
+		}
+	}
+
+	return alpha
+}
+
+// solveChild is a position after one of the moves of its parent.
+type solveChild struct {
+	position Position
+	move     [2]int
+	replies  int
+}
+
+// orderedChildren returns the positions after each of the moves, ordered by the number of replies
+// that the opponent has, fewest first.
+func orderedChildren(position Position, player Disk, moves Bitboard) []solveChild {
+	children := make([]solveChild, 0, moves.Count())
+	opponent := player%2 + 1
+
+	for _, square := range moves.Squares() {
+		child, _ := position.ApplyMove(square[0], square[1], player)
+		children = append(children, solveChild{
+			position: child,
+			move:     square,
+			replies:  child.LegalMoves(opponent).Count(),
+		})
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].replies < children[j].replies
+	})
+
+	return children
+}
+
+func (s *solver) boardRoot(board Board, player Disk) (int, [2]int) {
+	limit := board.size * board.size
+
+	moves := LegalMoves(board, player)
+	if len(moves) == 0 {
+		return s.board(board, player, -limit, limit, false), [2]int{-1, -1}
+	}
+
+	best, bestMove := -limit-1, [2]int{-1, -1}
+
+	for _, child := range orderedBoardChildren(board, player, moves) {
+		value := -s.board(child.board, player%2+1, -limit, -best, false)
+		if s.stopped {
+			break
+		}
+		if value > best {
+			best, bestMove = value, child.move
+		}
+	}
+
+	return best, bestMove
+}
+
+func (s *solver) board(board Board, player Disk, alpha, beta int, passed bool) int {
+	if !s.visit() {
+		return 0
+	}
+
+	opponent := player%2 + 1
+	moved := false
+
+	if board.Empties() > solveOrderingEmpties {
+		if moves := LegalMoves(board, player); len(moves) > 0 {
+			moved = true
+			for _, child := range orderedBoardChildren(board, player, moves) {
+				if value := -s.board(child.board, opponent, -beta, -alpha, false); value > alpha {
+					alpha = value
+					if alpha >= beta {
+						break
+					}
+				}
+			}
+		}
+	} else {
+		for x := 0; x < board.size && alpha < beta; x++ {
+			for y := 0; y < board.size && alpha < beta; y++ {
+				child, updated := ApplyMove(board, x, y, player)
+				if !updated {
+					continue
+				}
+
+				moved = true
+				if value := -s.board(child, opponent, -beta, -alpha, false); value > alpha {
+					alpha = value
+				}
+			}
+		}
+	}
+
+	if moved {
+		return alpha
+	}
+
+	if passed {
+		p1, p2 := KeepScore(board)
+		if player == Player2 {
+			p1, p2 = p2, p1
+		}
+		return s.sign * (p1 - p2)
+	}
+
+	return -s.board(board, opponent, -beta, -alpha, true)
+}
+
+// solveBoardChild is like solveChild, for boards that do not fit in a Position.
+type solveBoardChild struct {
+	board   Board
+	move    [2]int
+	replies int
+}
+
+// orderedBoardChildren is like orderedChildren, for boards that do not fit in a Position.
+func orderedBoardChildren(board Board, player Disk, moves []Move) []solveBoardChild {
+	children := make([]solveBoardChild, 0, len(moves))
+	opponent := player%2 + 1
+
+	for _, move := range moves {
+		child, _ := ApplyMove(board, move.X, move.Y, player)
+		children = append(children, solveBoardChild{
+			board:   child,
+			move:    [2]int{move.X, move.Y},
+			replies: len(LegalMoves(child, opponent)),
+		})
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].replies < children[j].replies
+	})
+
+	return children
+}
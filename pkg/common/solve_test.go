@@ -0,0 +1,170 @@
+package common_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+// bruteForce returns the final disk differential for the player with perfect play, by searching
+// every continuation of the game without any pruning.
+func bruteForce(board Board, player Disk, variant Variant, passed bool) int {
+	opponent := player%2 + 1
+
+	moves := LegalMoves(board, player)
+	if len(moves) == 0 {
+		if passed {
+			p1, p2 := KeepScore(board)
+			if player == Player2 {
+				return p2 - p1
+			}
+			return p1 - p2
+		}
+		return -bruteForce(board, opponent, variant, true)
+	}
+
+	var best int
+	for i, move := range moves {
+		child, _ := ApplyMove(board, move.X, move.Y, player)
+		differential := -bruteForce(child, opponent, variant, false)
+
+		if i == 0 || (variant == AntiOthello && differential < best) || (variant != AntiOthello && differential > best) {
+			best = differential
+		}
+	}
+
+	return best
+}
+
+// endgamePositions returns positions with few empty squares, reached by playing random moves.
+func endgamePositions(tb testing.TB, size, empties int, seed int64) []Match {
+	tb.Helper()
+
+	rng := rand.New(rand.NewSource(seed))
+	var matches []Match
+
+	for len(matches) < 10 {
+		match := NewMatch(size)
+		playRandomMoves(tb, rng, &match, match.Board.Empties()-empties)
+
+		if !match.IsOver() {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+func TestSolve(t *testing.T) {
+	for _, size := range []int{6, DefaultBoardSize} {
+		for _, variant := range Variants {
+			for _, match := range endgamePositions(t, size, 8, int64(size)) {
+				solution, err := Solve(context.Background(), match.Board, match.Player, variant)
+				if err != nil {
+					t.Fatalf("Solve() got error: %v", err)
+				}
+
+				want := bruteForce(match.Board, match.Player, variant, false)
+				if solution.Differential != want {
+					t.Errorf("Solve() of %s on a board of size %d got differential %d, want %d\n%v", variant, size, solution.Differential, want, match.Board)
+					continue
+				}
+
+				// The best move has to lead to the same outcome.
+				child, ok := ApplyMove(match.Board, solution.X, solution.Y, match.Player)
+				if !ok {
+					t.Errorf("Solve() of %s on a board of size %d got illegal move (%d, %d)", variant, size, solution.X, solution.Y)
+					continue
+				}
+				if got := -bruteForce(child, match.Player%2+1, variant, false); got != want {
+					t.Errorf("Solve() of %s on a board of size %d got move (%d, %d) with differential %d, want %d", variant, size, solution.X, solution.Y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSolveWinner(t *testing.T) {
+	// Player 1 moves to (2, 0) and takes every disk, so player 1 wins 3 to 0, but loses the
+	// anti-othello game.
+	board := buildTestBoard([]move{{0, 0}}, []move{{1, 0}})
+
+	tests := []struct {
+		variant Variant
+		player  Disk
+		want    Solution
+	}{
+		{Standard, Player1, Solution{X: 2, Y: 0, Differential: 3, Winner: Player1}},
+		{AntiOthello, Player1, Solution{X: 2, Y: 0, Differential: 3, Winner: Player2}},
+		{Standard, Player2, Solution{X: 2, Y: 0, Differential: -3, Winner: Player1}},
+	}
+
+	for _, tt := range tests {
+		got, err := Solve(context.Background(), board, tt.player, tt.variant)
+		if err != nil {
+			t.Fatalf("Solve() got error: %v", err)
+		}
+		if tt.player == Player2 {
+			// Player 2 has no legal moves and has to pass.
+			tt.want.X, tt.want.Y = -1, -1
+		}
+		if got != tt.want {
+			t.Errorf("Solve() of %s for player %d got %+v, want %+v", tt.variant, tt.player, got, tt.want)
+		}
+	}
+}
+
+func TestSolveTooManyPlayers(t *testing.T) {
+	if _, err := Solve(context.Background(), NewMatchForPlayers(DefaultBoardSize, 3).Board, Player1, Standard); err != ErrTooManyPlayers {
+		t.Errorf("Solve() got error %v, want %v", err, ErrTooManyPlayers)
+	}
+}
+
+func TestSolveCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, size := range []int{6, 10} {
+		match := endgamePositions(t, size, 20, 1)[0]
+		if _, err := Solve(ctx, match.Board, match.Player, Standard); err != context.Canceled {
+			t.Errorf("Solve() on a board of size %d with a canceled context got error %v, want %v", size, err, context.Canceled)
+		}
+	}
+
+	// The solver also gives up partway through.
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	match := endgamePositions(t, 10, 40, 1)[0]
+	if _, err := Solve(ctx, match.Board, match.Player, Standard); err != context.DeadlineExceeded {
+		t.Errorf("Solve() of a long endgame with a deadline got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	matches := endgamePositions(b, DefaultBoardSize, 14, 1)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		match := matches[i%len(matches)]
+		_, _ = Solve(context.Background(), match.Board, match.Player, Standard)
+	}
+}
+
+func TestMatchSolve(t *testing.T) {
+	match := NewMatchFromPosition(buildTestBoard([]move{{0, 0}}, []move{{1, 0}}), Player1)
+	match.SetVariant(AntiOthello)
+
+	if got, err := match.Solve(context.Background()); err != nil || got.Winner != Player2 {
+		t.Errorf("Solve() got %+v, %v, want player 2 to win", got, err)
+	}
+
+	_ = match.Play(2, 0)
+
+	if _, err := match.Solve(context.Background()); err != ErrGameOver {
+		t.Errorf("Solve() after the game is over got error %v, want %v", err, ErrGameOver)
+	}
+}
@@ -0,0 +1,76 @@
+package common
+
+// axes are the pairs of opposite directions that a line of disks can run along, as indexes into
+// directions.
+var axes = [4][2]int{{0, 1}, {2, 3}, {4, 7}, {5, 6}}
+
+// allSquares is every square of a Bitboard.
+const allSquares = ^Bitboard(0)
+
+// Neighbors returns the squares that are next to a square in the set in any of the 8 directions,
+// not counting the squares of the set itself.
+func (b Bitboard) Neighbors() Bitboard {
+	var neighbors Bitboard
+	for _, d := range directions {
+		neighbors |= d.apply(b)
+	}
+	return neighbors &^ b
+}
+
+// Stable returns the disks of the player that can never be flipped, no matter how the game goes
+// on. It finds most, but not all, of them: a disk is counted as stable if along each of the 4 lines
+// through it, either the line is full, or the disk is next to the edge of the board, a blocked
+// square or another stable disk of the player.
+func (p Position) Stable(player Disk) Bitboard {
+	own := p.Disks(player)
+	filled := p.P1 | p.P2
+
+	// walls[i] are the squares whose neighbor in direction i is off the board or blocked.
+	var walls [8]Bitboard
+	for i := range directions {
+		walls[i] = ^opposite(i).apply(allSquares) | opposite(i).apply(p.Blocked)
+	}
+
+	// full[a] are the squares whose line along axis a has no empty squares between the walls.
+	var full [4]Bitboard
+	for a, axis := range axes {
+		full[a] = allSquares
+		for _, i := range axis {
+			reach := filled & walls[i]
+			for j := 0; j < bitboardSize; j++ {
+				reach |= filled & opposite(i).apply(reach)
+			}
+			full[a] &= reach
+		}
+	}
+
+	var stable Bitboard
+	for {
+		next := own
+		for a, axis := range axes {
+			anchored := full[a]
+			for _, i := range axis {
+				anchored |= walls[i] | opposite(i).apply(stable)
+			}
+			next &= anchored
+		}
+
+		if next == stable {
+			return stable
+		}
+		stable = next
+	}
+}
+
+// opposite returns the direction opposite to the direction at index i of directions.
+func opposite(i int) direction {
+	for _, axis := range axes {
+		switch i {
+		case axis[0]:
+			return directions[axis[1]]
+		case axis[1]:
+			return directions[axis[0]]
+		}
+	}
+	panic("invalid direction")
+}
@@ -0,0 +1,87 @@
+package common_test
+
+import (
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestNeighbors(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Bitboard
+		want int
+	}{
+		{name: "corner", set: SquareBit(0, 0), want: 3},
+		{name: "edge", set: SquareBit(3, 0), want: 5},
+		{name: "middle", set: SquareBit(3, 3), want: 8},
+		{name: "pair", set: SquareBit(3, 3) | SquareBit(4, 3), want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.set.Neighbors()
+			if got.Count() != tt.want || got&tt.set != 0 {
+				t.Errorf("Neighbors() got %v, want %d squares that are not in the set", got.Squares(), tt.want)
+			}
+		})
+	}
+}
+
+func TestStable(t *testing.T) {
+	tests := []struct {
+		name  string
+		board string
+		want  []move
+	}{
+		{
+			name:  "opening",
+			board: "8/8/8/3xo3/3ox3/8/8/8",
+			want:  nil,
+		},
+		{
+			name:  "corner",
+			board: "x7/8/8/3xo3/3ox3/8/8/8",
+			want:  []move{{0, 0}},
+		},
+		{
+			name:  "edge anchored by a corner",
+			board: "xxxo4/x7/8/3xo3/3ox3/8/8/8",
+			want:  []move{{0, 0}, {1, 0}, {2, 0}, {0, 1}},
+		},
+		{
+			name:  "next to blocked squares",
+			board: "#x6/#7/8/3xo3/3ox3/8/8/8",
+			want:  []move{{1, 0}},
+		},
+		{
+			name:  "edge without a corner",
+			board: "1xxx4/8/8/3xo3/3ox3/8/8/8",
+			want:  nil,
+		},
+		{
+			name:  "full edge",
+			board: "oxxxxxxo/8/8/3xo3/3ox3/8/8/8",
+			want:  []move{{1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, _, err := ParseBoard(tt.board)
+			if err != nil {
+				t.Fatal(err)
+			}
+			position, _ := NewPosition(board)
+
+			var want Bitboard
+			for _, m := range tt.want {
+				want |= SquareBit(m[0], m[1])
+			}
+
+			if got := position.Stable(Player1); got != want {
+				t.Errorf("Stable() got %v, want %v", got.Squares(), want.Squares())
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package common
+
+// Symmetry is one of the 8 ways that a square board can be rotated or reflected onto itself. Since
+// the rules of the game are the same on a rotated or reflected board, so are the outcomes of the
+// positions, which lets caches and books store one entry for all of them.
+type Symmetry uint8
+
+const (
+	Identity Symmetry = iota
+	// Rotate90 rotates the board a quarter turn clockwise.
+	Rotate90
+	Rotate180
+	Rotate270
+	// FlipX reflects the board left to right.
+	FlipX
+	// FlipY reflects the board top to bottom.
+	FlipY
+	// FlipDiagonal reflects the board across the diagonal from the top-left corner.
+	FlipDiagonal
+	// FlipAntiDiagonal reflects the board across the diagonal from the top-right corner.
+	FlipAntiDiagonal
+)
+
+// Symmetries are all of the symmetries of a board, starting with Identity.
+var Symmetries = [...]Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipX, FlipY, FlipDiagonal, FlipAntiDiagonal}
+
+// Square returns the square that (x, y) is moved to by the symmetry, on a board of the given size.
+func (s Symmetry) Square(size, x, y int) (int, int) {
+	last := size - 1
+
+	switch s {
+	case Rotate90:
+		return last - y, x
+	case Rotate180:
+		return last - x, last - y
+	case Rotate270:
+		return y, last - x
+	case FlipX:
+		return last - x, y
+	case FlipY:
+		return x, last - y
+	case FlipDiagonal:
+		return y, x
+	case FlipAntiDiagonal:
+		return last - y, last - x
+	default:
+		return x, y
+	}
+}
+
+// Inverse returns the symmetry that undoes s.
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	default:
+		return s
+	}
+}
+
+// Transform returns a copy of the board with the symmetry applied.
+func (b Board) Transform(s Symmetry) Board {
+	transformed := NewBoard(b.size)
+
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if disk := b.disks[x][y]; disk != 0 {
+				tx, ty := s.Square(b.size, x, y)
+				transformed.set(tx, ty, disk)
+			}
+		}
+	}
+
+	return transformed
+}
+
+// Canonical returns the symmetry of the board with the lowest hash, which is the same for every
+// symmetry of the board. It also returns the symmetry that transforms the board into its canonical
+// form, so that moves on the canonical board can be mapped back with Symmetry.Inverse.
+func (b Board) Canonical() (Board, Symmetry) {
+	canonical, symmetry := b, Identity
+
+	for _, s := range Symmetries[1:] {
+		if transformed := b.Transform(s); transformed.hash < canonical.hash {
+			canonical, symmetry = transformed, s
+		}
+	}
+
+	return canonical, symmetry
+}
+
+// CanonicalKey returns the Key of the canonical form of the board, which is the same for every
+// symmetry of the board.
+func (b Board) CanonicalKey(player Disk) uint64 {
+	canonical, _ := b.Canonical()
+	return canonical.Key(player)
+}
@@ -0,0 +1,133 @@
+package common_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+// randomPositions returns the positions of random games on each board size, after every move.
+func randomPositions(t *testing.T, seed int64) []Board {
+	rng := rand.New(rand.NewSource(seed))
+
+	var boards []Board
+
+	for _, size := range BoardSizes {
+		for game := 0; game < 5; game++ {
+			m := NewMatch(size)
+
+			for !m.IsOver() {
+				playRandomMoves(t, rng, &m, 1)
+				boards = append(boards, m.Board)
+			}
+		}
+	}
+
+	return boards
+}
+
+func TestCanonical(t *testing.T) {
+	for _, board := range randomPositions(t, 1) {
+		want, _ := board.Canonical()
+
+		for _, s := range Symmetries {
+			transformed := board.Transform(s)
+
+			got, symmetry := transformed.Canonical()
+			if got != want {
+				t.Fatalf("Canonical() of %v under %d got %v, want %v", board, s, got, want)
+			}
+
+			if transformed.Transform(symmetry) != got {
+				t.Fatalf("Canonical() of %v under %d got symmetry %d which does not transform the board", board, s, symmetry)
+			}
+
+			for _, player := range []Disk{Player1, Player2} {
+				if got, want := transformed.CanonicalKey(player), board.CanonicalKey(player); got != want {
+					t.Fatalf("CanonicalKey() of %v under %d got %x, want %x", board, s, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSymmetryInverse(t *testing.T) {
+	for _, board := range randomPositions(t, 2) {
+		for _, s := range Symmetries {
+			if got := board.Transform(s).Transform(s.Inverse()); got != board {
+				t.Fatalf("Transform(%d) then Transform(%d) got %v, want %v", s, s.Inverse(), got, board)
+			}
+		}
+	}
+}
+
+func TestSymmetryLegalMoves(t *testing.T) {
+	for _, board := range randomPositions(t, 3) {
+		for _, s := range Symmetries {
+			transformed := board.Transform(s)
+
+			for _, player := range []Disk{Player1, Player2} {
+				moves := LegalMoves(board, player)
+				if got := len(LegalMoves(transformed, player)); got != len(moves) {
+					t.Fatalf("LegalMoves() of %v under %d got %d moves, want %d", board, s, got, len(moves))
+				}
+
+				for _, move := range moves {
+					x, y := s.Square(board.Size(), move.X, move.Y)
+					if len(Flips(transformed, x, y, player)) != len(move.Flips) {
+						t.Fatalf("Flips() of %v under %d at (%d, %d) does not match (%d, %d)", board, s, x, y, move.X, move.Y)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestHashIncremental(t *testing.T) {
+	seen := make(map[uint64]Board)
+
+	for _, board := range randomPositions(t, 4) {
+		// Rebuild the board from scratch, to check the hash that was kept up to date by each move.
+		rebuilt := NewBoard(board.Size())
+		for x := 0; x < board.Size(); x++ {
+			for y := 0; y < board.Size(); y++ {
+				if disk := board.At(x, y); disk != 0 {
+					rebuilt.Set(x, y, disk)
+				}
+			}
+		}
+
+		if rebuilt.Hash() != board.Hash() {
+			t.Fatalf("Hash() of %v got %x, want %x", board, board.Hash(), rebuilt.Hash())
+		}
+
+		if board.Key(Player1) == board.Key(Player2) {
+			t.Fatalf("Key() of %v is the same for both players", board)
+		}
+
+		if other, ok := seen[board.Hash()]; ok && other != board {
+			t.Fatalf("Hash() of %v and %v are both %x", board, other, board.Hash())
+		}
+		seen[board.Hash()] = board
+	}
+}
+
+func TestHashJSON(t *testing.T) {
+	board := NewMatch(DefaultBoardSize).Board
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Board
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Hash() != board.Hash() {
+		t.Errorf("Hash() after JSON round trip got %x, want %x", got.Hash(), board.Hash())
+	}
+}
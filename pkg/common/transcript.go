@@ -0,0 +1,99 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Transcripts use the standard algebraic Othello notation, where each move is written as a column
+// letter followed by a row number, such as "f5". A game is written as its moves one after another
+// with no separator, such as "f5d6c3d3c4". Passes are implicit: when a player has no legal moves,
+// the next move in the transcript belongs to their opponent.
+//
+// Rows are numbered from the bottom of the board. This puts the disks of the opening position on
+// the same squares as in standard Othello, so that real games can be read as they are published.
+
+// FormatSquare returns the name of the square (x, y) on a board of the given size, such as "f5".
+func FormatSquare(size, x, y int) string {
+	return fmt.Sprintf("%c%d", 'a'+x, size-y)
+}
+
+// ParseSquare returns the coordinates of a square on a board of the given size from its name.
+func ParseSquare(size int, name string) (x, y int, err error) {
+	if len(name) < 2 {
+		return 0, 0, fmt.Errorf("invalid square %q", name)
+	}
+
+	x = int(unicode.ToLower(rune(name[0])) - 'a')
+
+	row, err := strconv.Atoi(name[1:])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid square %q", name)
+	}
+
+	y = size - row
+
+	if x < 0 || x >= size || y < 0 || y >= size {
+		return 0, 0, fmt.Errorf("square %q is not on a board of size %d", name, size)
+	}
+
+	return x, y, nil
+}
+
+// ParseTranscript plays a transcript from the standard opening position on a board of the default
+// size, and returns the resulting Match.
+func ParseTranscript(transcript string) (Match, error) {
+	m := NewMatch(DefaultBoardSize)
+	err := m.PlayTranscript(transcript)
+	return m, err
+}
+
+// PlayTranscript plays each move of a transcript in order. If a move is invalid or illegal, the
+// returned error names it, and the Match is left as it was after the previous move.
+func (m *Match) PlayTranscript(transcript string) error {
+	for i, name := range splitTranscript(transcript) {
+		x, y, err := ParseSquare(m.Board.Size(), name)
+		if err == nil {
+			err = m.Play(x, y)
+		}
+		if err != nil {
+			return fmt.Errorf("move %d (%s): %w", i+1, name, err)
+		}
+	}
+
+	return nil
+}
+
+// Transcript returns the moves that have been played in the Match, in transcript notation.
+func (m *Match) Transcript() string {
+	var sb strings.Builder
+
+	for _, turn := range m.History {
+		if !turn.Pass {
+			sb.WriteString(FormatSquare(m.Board.Size(), turn.X, turn.Y))
+		}
+	}
+
+	return sb.String()
+}
+
+// splitTranscript splits a transcript into the names of its squares. Whitespace is ignored, and
+// each name starts at a letter.
+func splitTranscript(transcript string) []string {
+	var names []string
+
+	for _, ch := range transcript {
+		switch {
+		case unicode.IsSpace(ch):
+			continue
+		case unicode.IsLetter(ch) || len(names) == 0:
+			names = append(names, string(unicode.ToLower(ch)))
+		default:
+			names[len(names)-1] += string(ch)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,99 @@
+package common_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/armsnyder/othelgo/pkg/common"
+)
+
+func TestParseSquare(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		x, y    int
+		wantErr bool
+	}{
+		{name: "a8", size: 8, x: 0, y: 0},
+		{name: "h1", size: 8, x: 7, y: 7},
+		{name: "F5", size: 8, x: 5, y: 3},
+		{name: "l12", size: 12, x: 11, y: 0},
+		{name: "i1", size: 8, wantErr: true},
+		{name: "a9", size: 8, wantErr: true},
+		{name: "a0", size: 8, wantErr: true},
+		{name: "a", size: 8, wantErr: true},
+		{name: "ab", size: 8, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, err := ParseSquare(tt.size, tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSquare() got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if x != tt.x || y != tt.y {
+				t.Errorf("ParseSquare() got (%d, %d), want (%d, %d)", x, y, tt.x, tt.y)
+			}
+			if got := FormatSquare(tt.size, x, y); got != strings.ToLower(tt.name) {
+				t.Errorf("FormatSquare() got %q, want %q", got, strings.ToLower(tt.name))
+			}
+		})
+	}
+}
+
+func TestParseTranscript(t *testing.T) {
+	m, err := ParseTranscript("f5d6c3d3c4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := buildTestBoard(
+		[]move{{2, 4}, {2, 5}, {3, 4}, {4, 3}, {4, 4}, {5, 3}},
+		[]move{{3, 2}, {3, 3}, {3, 5}},
+	)
+
+	if m.Board != want {
+		t.Errorf("ParseTranscript() got board %v, want %v", m.Board, want)
+	}
+
+	if got := m.Transcript(); got != "f5d6c3d3c4" {
+		t.Errorf("Transcript() got %q, want %q", got, "f5d6c3d3c4")
+	}
+}
+
+func TestParseTranscriptIllegalMove(t *testing.T) {
+	_, err := ParseTranscript("f5 d6 c3 a1 c4")
+
+	if !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("ParseTranscript() got error %v, want %v", err, ErrIllegalMove)
+	}
+
+	if err == nil || !strings.HasPrefix(err.Error(), "move 4 (a1)") {
+		t.Errorf("ParseTranscript() got error %v, want it to name move 4 (a1)", err)
+	}
+}
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	// Random games often contain passes, which the transcript leaves out.
+	rng := rand.New(rand.NewSource(1))
+
+	for _, size := range BoardSizes {
+		for game := 0; game < 20; game++ {
+			m := NewMatch(size)
+			playRandomMoves(t, rng, &m, m.Board.Empties())
+
+			replay := NewMatch(size)
+			if err := replay.PlayTranscript(m.Transcript()); err != nil {
+				t.Fatalf("PlayTranscript(%q) got error %v", m.Transcript(), err)
+			}
+
+			if replay.Board != m.Board || len(replay.History) != len(m.History) {
+				t.Errorf("PlayTranscript(%q) got board %v, want %v", m.Transcript(), replay.Board, m.Board)
+			}
+		}
+	}
+}
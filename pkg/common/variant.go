@@ -0,0 +1,64 @@
+package common
+
+// Variant is a set of rules that decides who wins a game. The zero value is the standard game.
+type Variant string
+
+const (
+	// Standard is the standard game, where the player with the most disks wins.
+	Standard = Variant("")
+
+	// AntiOthello is the reverse game, where the player with the fewest disks wins.
+	AntiOthello = Variant("anti")
+)
+
+// Variants are the variants that a game can be played with.
+var Variants = []Variant{Standard, AntiOthello}
+
+// ValidVariant returns true if variant is one of Variants.
+func ValidVariant(variant Variant) bool {
+	for _, v := range Variants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
+
+// Winner returns the player who wins a game that ended with the given scores, where the score of
+// player p is at index p-1. It returns zero if the game is a draw, which is when more than one
+// player has the best score.
+func (v Variant) Winner(scores []int) Disk {
+	if len(scores) == 0 {
+		return 0
+	}
+
+	best := 0
+	for i := range scores {
+		if v.better(scores[i], scores[best]) {
+			best = i
+		}
+	}
+
+	for i := range scores {
+		if i != best && scores[i] == scores[best] {
+			return 0
+		}
+	}
+
+	return Disk(best + 1)
+}
+
+// better returns true if a score of a is better than a score of b.
+func (v Variant) better(a, b int) bool {
+	if v == AntiOthello {
+		return a < b
+	}
+	return a > b
+}
+
+func (v Variant) String() string {
+	if v == AntiOthello {
+		return "anti-othello"
+	}
+	return "standard"
+}
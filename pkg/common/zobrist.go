@@ -0,0 +1,85 @@
+package common
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// Boards are hashed using Zobrist hashing. Each disk on each square has a random key, and the hash
+// of a board is the XOR of the keys of its disks. Placing or flipping a disk only has to XOR the
+// keys of the square, so the hash is kept up to date as moves are applied instead of being
+// recomputed.
+//
+// The keys are generated from a fixed seed, so hashes are stable between runs of the program and
+// can be stored, such as in an opening book.
+
+// zobristSeed is the seed of the Zobrist keys. Changing it invalidates any stored hashes.
+const zobristSeed = 0x0e7e10
+
+var (
+	// zobristKeys holds the key of each disk on each square, indexed by [x][y][disk]. The keys of
+	// an empty square are zero.
+	zobristKeys [MaxBoardSize][MaxBoardSize][Blocked + 1]uint64
+
+	// zobristTurns holds the key that is mixed into a hash when it is each player's turn, indexed by
+	// player. The key of player 1 is zero, so that the hash of a board is its key for player 1.
+	zobristTurns [MaxPlayers + 1]uint64
+)
+
+func init() {
+	rng := rand.New(rand.NewSource(zobristSeed))
+
+	for x := range zobristKeys {
+		for y := range zobristKeys[x] {
+			for disk := Player1; disk <= Blocked; disk++ {
+				zobristKeys[x][y][disk] = rng.Uint64()
+			}
+		}
+	}
+
+	for player := Player2; player <= MaxPlayers; player++ {
+		zobristTurns[player] = rng.Uint64()
+	}
+}
+
+// zobristKey returns the key of a disk on the square (x, y), or zero if the disk is not valid.
+func zobristKey(x, y int, disk Disk) uint64 {
+	if int(disk) >= len(zobristKeys[x][y]) {
+		return 0
+	}
+	return zobristKeys[x][y][disk]
+}
+
+// Hash returns the Zobrist hash of the disks on the board. Equal boards always have equal hashes.
+func (b Board) Hash() uint64 {
+	return b.hash
+}
+
+// Key returns a hash of the board and the player whose turn it is, which is suitable for use as a
+// key of a cache of positions.
+func (b Board) Key(player Disk) uint64 {
+	return b.hash ^ zobristTurn(player)
+}
+
+// Key is the Position equivalent of Board.Key. It is equal to the key of the same Board.
+func (p Position) Key(player Disk) uint64 {
+	hash := zobristTurn(player)
+
+	for disk, squares := range [...]Bitboard{Player1: p.P1, Player2: p.P2, Blocked: p.Blocked} {
+		for squares != 0 {
+			i := bits.TrailingZeros64(uint64(squares))
+			squares &= squares - 1
+			hash ^= zobristKeys[i%bitboardSize][i/bitboardSize][disk]
+		}
+	}
+
+	return hash
+}
+
+// zobristTurn returns the key of the player's turn, or zero if the player is not valid.
+func zobristTurn(player Disk) uint64 {
+	if int(player) >= len(zobristTurns) {
+		return 0
+	}
+	return zobristTurns[player]
+}
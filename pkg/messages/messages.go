@@ -19,6 +19,10 @@ var manifest = []interface{}{
 	(*UpdateBoard)(nil),
 	(*Error)(nil),
 	(*Decorate)(nil),
+	(*RequestHint)(nil),
+	(*Analysis)(nil),
+	(*RequestGameAnalysis)(nil),
+	(*GameAnalysis)(nil),
 }
 
 type Hello struct {
@@ -27,16 +31,68 @@ type Hello struct {
 
 type HostGame struct {
 	Nickname string `json:"nickname" validate:"required,max=10,alphanumspace,lowercase"`
+
+	// AllowHints is whether the host allows hints in the game. Hints are only given in a
+	// multiplayer game if both players allow them.
+	AllowHints bool `json:"allowHints,omitempty"`
+
+	GameSettings
 }
 
 type StartSoloGame struct {
 	Nickname   string `json:"nickname" validate:"required,max=10,alphanumspace,lowercase"`
 	Difficulty int    `json:"difficulty" validate:"oneof=0 1 2"`
+
+	// Engine is the name of the AI engine to play against, or empty for the default engine.
+	Engine string `json:"engine,omitempty" validate:"max=32"`
+
+	// Color is the color that the human plays, or black if it is empty.
+	Color Color `json:"color,omitempty" validate:"omitempty,oneof=black white random"`
+
+	GameSettings
+}
+
+// Color is a choice of which player the human plays in a solo game. Black is player 1, who moves
+// first, and white is player 2.
+type Color string
+
+const (
+	Black       Color = "black"
+	White       Color = "white"
+	RandomColor Color = "random"
+)
+
+// GameSettings are the settings of a new game, which are shared by HostGame and StartSoloGame.
+// The zero value is a standard game.
+type GameSettings struct {
+	BoardSize int            `json:"boardSize" validate:"omitempty,boardsize"`
+	Variant   common.Variant `json:"variant" validate:"variant"`
+
+	// Handicap gives corners to HandicapPlayer, which must be one of the Players. If it is zero,
+	// the corners go to the human in a solo game, or to player 1 otherwise. The corners must be
+	// empty on a custom Board.
+	Handicap       common.Handicap `json:"handicap" validate:"handicap"`
+	HandicapPlayer common.Disk     `json:"handicapPlayer" validate:"omitempty,oneof=1 2 3 4"`
+
+	// Board is a custom starting position, which replaces the standard opening. It may contain
+	// preset obstacles. FirstPlayer moves first, or player 1 if it is zero.
+	Board       *common.Board `json:"board,omitempty"`
+	FirstPlayer common.Disk   `json:"firstPlayer" validate:"omitempty,oneof=1 2"`
+
+	// Obstacles is the number of blocked squares to place at random on the starting position.
+	Obstacles int `json:"obstacles" validate:"min=0,max=8"`
+
+	// Players is the number of players, or 2 if it is zero. Games of more than two players can
+	// only be played solo, where the AI plays every player except the human.
+	Players int `json:"players" validate:"omitempty,min=2,max=4"`
 }
 
 type JoinGame struct {
 	Nickname string `json:"nickname" validate:"required,max=10,alphanumspace,lowercase,nefield=Host"`
 	Host     string `json:"host" validate:"required,max=10,alphanumspace,lowercase"`
+
+	// AllowHints is whether the opponent allows hints in the game, like HostGame.AllowHints.
+	AllowHints bool `json:"allowHints,omitempty"`
 }
 
 type Joined struct {
@@ -61,17 +117,24 @@ type OpenGames struct {
 type PlaceDisk struct {
 	Nickname string `json:"nickname" validate:"required,max=10,alphanumspace,lowercase"`
 	Host     string `json:"host" validate:"required,max=10,alphanumspace,lowercase"`
-	X        int    `json:"x" validate:"min=0,max=7"`
-	Y        int    `json:"y" validate:"min=0,max=7"`
+	X        int    `json:"x" validate:"min=0,max=11"`
+	Y        int    `json:"y" validate:"min=0,max=11"`
 }
 
 type UpdateBoard struct {
-	Board   common.Board `json:"board"`
-	Player  common.Disk  `json:"player"`
-	X       int          `json:"x"`
-	Y       int          `json:"y"`
-	P1Score int          `json:"p1score"`
-	P2Score int          `json:"p2score"`
+	Board  common.Board `json:"board"`
+	Player common.Disk  `json:"player"`
+	X      int          `json:"x"`
+	Y      int          `json:"y"`
+
+	// Scores are the number of disks that each player has, where the score of player p is at index
+	// p-1. There is one score for every player in the game.
+	Scores []int `json:"scores"`
+
+	Variant common.Variant `json:"variant"`
+
+	// HumanPlayer is the player of the human in a solo game, or zero in a multiplayer game.
+	HumanPlayer common.Disk `json:"humanPlayer,omitempty"`
 }
 
 type Error struct {
@@ -81,3 +144,56 @@ type Error struct {
 type Decorate struct {
 	Decoration string `json:"decoration"`
 }
+
+// RequestHint asks the server to analyze the position for the player whose turn it is. Hints are
+// always allowed in solo games, but only in multiplayer games that both players allow them in.
+type RequestHint struct {
+	Nickname string `json:"nickname" validate:"required,max=10,alphanumspace,lowercase"`
+	Host     string `json:"host" validate:"required,max=10,alphanumspace,lowercase"`
+}
+
+// Analysis is the reply to RequestHint. X and Y are the recommended move.
+type Analysis struct {
+	Moves []MoveScore `json:"moves"`
+	X     int         `json:"x"`
+	Y     int         `json:"y"`
+}
+
+// MoveScore is a legal move and its score, which is how good the move is for the player who makes
+// it. Only the order of the scores is meaningful, since they are on the scale of the AI's
+// evaluation.
+type MoveScore struct {
+	X     int     `json:"x"`
+	Y     int     `json:"y"`
+	Score float64 `json:"score"`
+}
+
+// RequestGameAnalysis asks the server to review every move of a game that is over.
+type RequestGameAnalysis struct {
+	Nickname string `json:"nickname" validate:"required,max=10,alphanumspace,lowercase"`
+	Host     string `json:"host" validate:"required,max=10,alphanumspace,lowercase"`
+}
+
+// GameAnalysis is the reply to RequestGameAnalysis, with one entry for every move of the game in
+// the order they were played. Passes are left out. If the server runs out of time, the last moves
+// are left out too.
+type GameAnalysis struct {
+	Moves []MoveAnalysis `json:"moves"`
+}
+
+// MoveAnalysis is the review of a move that was played. Score is how good the move was for the
+// player, on the same scale as MoveScore, and BestScore is the score of the best move (BestX,
+// BestY). Swing is how much worse the move was than the best move.
+type MoveAnalysis struct {
+	Player    common.Disk `json:"player"`
+	X         int         `json:"x"`
+	Y         int         `json:"y"`
+	Score     float64     `json:"score"`
+	BestX     int         `json:"bestX"`
+	BestY     int         `json:"bestY"`
+	BestScore float64     `json:"bestScore"`
+	Swing     float64     `json:"swing"`
+
+	// Mistake is "inaccuracy" or "blunder" if the swing was large enough, or empty otherwise.
+	Mistake string `json:"mistake,omitempty"`
+}
@@ -45,3 +45,9 @@ func TestUnmarshal(t *testing.T) {
 		assert.Equal(t, "0.0.0", w.Message.(*Hello).Version)
 	}
 }
+
+func TestMarshalGameSettings(t *testing.T) {
+	b, err := json.Marshal(Wrapper{Message: HostGame{Nickname: "flame", GameSettings: GameSettings{BoardSize: 6}}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"action":"hostGame","nickname":"flame","boardSize":6,"variant":"","handicap":"","handicapPlayer":0,"firstPlayer":0,"obstacles":0,"players":0}`, string(b))
+}
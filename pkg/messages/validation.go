@@ -4,6 +4,8 @@ import (
 	"regexp"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/armsnyder/othelgo/pkg/common"
 )
 
 var (
@@ -16,13 +18,25 @@ var (
 func RegisterCustomValidations(v *validator.Validate) {
 	registerRegexpValidation(v, "alphanumspace", alphaNumSpacePattern)
 	registerRegexpValidation(v, "semver", semVerPattern)
+	registerValidation(v, "boardsize", func(fl validator.FieldLevel) bool {
+		return common.ValidBoardSize(int(fl.Field().Int()))
+	})
+	registerValidation(v, "variant", func(fl validator.FieldLevel) bool {
+		return common.ValidVariant(common.Variant(fl.Field().String()))
+	})
+	registerValidation(v, "handicap", func(fl validator.FieldLevel) bool {
+		return common.ValidHandicap(common.Handicap(fl.Field().String()))
+	})
 }
 
 func registerRegexpValidation(v *validator.Validate, tag string, pattern *regexp.Regexp) {
-	err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+	registerValidation(v, tag, func(fl validator.FieldLevel) bool {
 		return pattern.MatchString(fl.Field().String())
 	})
-	if err != nil {
+}
+
+func registerValidation(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
 		panic(err)
 	}
 }
@@ -1,150 +1,116 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"math"
+	"os"
+	"strconv"
+	"time"
 
-	"github.com/armsnyder/othelgo/pkg/common"
+	"github.com/armsnyder/othelgo/pkg/ai"
+	"github.com/armsnyder/othelgo/pkg/messages"
 )
 
-// doAIPlayerMove takes a turn as the AI player.
-func doAIPlayerMove(board common.Board, difficulty int) (common.Board, [2]int) {
-	aiState := &aiGameState{
-		board:            board,
-		maximizingPlayer: 2,
-		turn:             2,
+// doAIPlayerMove picks the coordinates of the next move of the AI player whose turn it is in a
+// solo game, using the engine and difficulty that the game was started with.
+func doAIPlayerMove(ctx context.Context, args Args, game game) ([2]int, error) {
+	engine, ok := ai.Lookup(game.Engine)
+	if !ok {
+		return [2]int{}, fmt.Errorf("unknown AI engine %q", game.Engine)
 	}
 
-	var depth int
-	switch difficulty {
-	default:
-		depth = 1
-	case 1:
-		depth = 4
-	case 2:
-		depth = 6
-	}
-
-	move := findMoveUsingMinimax(aiState, depth)
-	return aiState.moves[move], aiState.moveLocations[move]
+	return engine.BestMove(ctx, game.Board, game.Player, aiLimits(args, game))
 }
 
-// aiGameState implements the othelgo domain-specific logic needed by the AI.
-type aiGameState struct {
-	board            common.Board
-	turn             common.Disk
-	maximizingPlayer common.Disk
-	moves            []common.Board
-	moveLocations    [][2]int
+// aiLimits returns the limits of the AI at the difficulty of the game, searching with the workers
+// of the server's Args.
+func aiLimits(args Args, game game) ai.Limits {
+	limits := ai.DifficultyLimits(game.Difficulty)
+	limits.Variant = game.Variant
+	limits.Workers = args.AIWorkers
+	return limits
 }
 
-func (a *aiGameState) Score() float64 {
-	p1, p2 := common.KeepScore(a.board)
+// aiWorkersEnv is the environment variable that sets the AIWorkers of the default Args.
+const aiWorkersEnv = "OTHELGO_AI_WORKERS"
 
-	if a.maximizingPlayer == 1 {
-		p1, p2 = p2, p1
+// defaultAIWorkers returns the number of AI workers that the environment asks for, or zero if it
+// does not ask for a valid number.
+func defaultAIWorkers() int {
+	value, ok := os.LookupEnv(aiWorkersEnv)
+	if !ok {
+		return 0
 	}
 
-	if common.GameOver(a.board) {
-		switch {
-		case p2 > p1:
-			return math.Inf(1)
-		case p1 < p2:
-			return math.Inf(-1)
-		default:
-			return 0
-		}
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 0 {
+		log.Printf("Ignoring invalid %s %q", aiWorkersEnv, value)
+		return 0
 	}
 
-	trueScoreDelta := float64(p2 - p1)
-	scoreModifier := a.scoreModifier(2) - a.scoreModifier(1)
-
-	// Modifier strength decreases as the board fills up.
-	scoreModifier *= a.percentFull()
-
-	return trueScoreDelta + scoreModifier
+	return workers
 }
 
-func (a *aiGameState) scoreModifier(player common.Disk) (score float64) {
-	endIndex := common.BoardSize - 1
+// hintLimits returns the limits of the analysis of a position for a hint, which is as strong as
+// the normal AI no matter the difficulty of the game. Every move is scored, so the search is
+// sequential.
+func hintLimits(game game) ai.Limits {
+	return ai.Limits{Variant: game.Variant, Time: 750 * time.Millisecond, Depth: 5}
+}
 
-	// Edges are valuable.
-	edgeScore := 0.5
-	for i := 1; i < endIndex; i++ {
-		if a.board[i][0] == player {
-			score += edgeScore
-		}
-		if a.board[0][i] == player {
-			score += edgeScore
-		}
-		if a.board[i][endIndex] == player {
-			score += edgeScore
-		}
-		if a.board[endIndex][i] == player {
-			score += edgeScore
+// newAnalysis returns an Analysis message for the scores of the moves of a position, which are
+// ordered from best to worst. The scores of won and lost games are infinite, which JSON cannot
+// represent, so they are made finite.
+func newAnalysis(scores []ai.MoveScore) messages.Analysis {
+	analysis := messages.Analysis{Moves: make([]messages.MoveScore, len(scores))}
+
+	for i, score := range scores {
+		analysis.Moves[i] = messages.MoveScore{
+			X:     score.Move[0],
+			Y:     score.Move[1],
+			Score: finite(score.Score),
 		}
 	}
 
-	// Corners are highly valuable.
-	cornerScore := float64(2)
-	if a.board[0][0] == player {
-		score += cornerScore
-	}
-	if a.board[0][endIndex] == player {
-		score += cornerScore
-	}
-	if a.board[endIndex][0] == player {
-		score += cornerScore
-	}
-	if a.board[endIndex][endIndex] == player {
-		score += cornerScore
-	}
+	analysis.X, analysis.Y = scores[0].Move[0], scores[0].Move[1]
 
-	return score
+	return analysis
 }
 
-func (a *aiGameState) percentFull() float64 {
-	freeCells := 0
-	for x := 0; x < common.BoardSize; x++ {
-		for y := 0; y < common.BoardSize; y++ {
-			if a.board[x][y] == 0 {
-				freeCells++
-			}
-		}
-	}
-	return float64(freeCells) / common.BoardSize / common.BoardSize
+// finite returns the score, with infinities replaced by the largest finite scores.
+func finite(score float64) float64 {
+	return math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, score))
 }
 
-func (a *aiGameState) AITurn() bool {
-	return a.turn == a.maximizingPlayer
-}
+// reviewBudget is the most time that the review of a whole game may take. It is shared between
+// the positions of the game, and the positions that are left when it runs out are not reviewed.
+const reviewBudget = 3 * time.Second
 
-func (a *aiGameState) MoveCount() int {
-	if a.moves == nil {
-		a.moves = []common.Board{}
-		for x := 0; x < common.BoardSize; x++ {
-			for y := 0; y < common.BoardSize; y++ {
-				if board, updated := common.ApplyMove(a.board, x, y, a.turn); updated {
-					a.moves = append(a.moves, board)
-					a.moveLocations = append(a.moveLocations, [2]int{x, y})
-				}
-			}
-		}
-	}
-
-	return len(a.moves)
+// reviewLimits returns the limits of the analysis of each position of a game that is reviewed.
+// A game has dozens of positions, so each one gets much less time than a hint.
+func reviewLimits(game game) ai.Limits {
+	return ai.Limits{Variant: game.Variant, Time: 100 * time.Millisecond, Depth: 4}
 }
 
-func (a *aiGameState) Move(i int) AIGameState {
-	a.MoveCount() // Lazy initialize moves
-
-	nextState := &aiGameState{
-		board: a.moves[i],
-		turn:  a.turn,
-	}
-
-	if common.HasMoves(a.moves[i], a.turn%2+1) {
-		nextState.turn = a.turn%2 + 1
+// newGameAnalysis returns a GameAnalysis message for the reviews of the moves of a game.
+func newGameAnalysis(reviews []ai.MoveReview) messages.GameAnalysis {
+	analysis := messages.GameAnalysis{Moves: make([]messages.MoveAnalysis, len(reviews))}
+
+	for i, review := range reviews {
+		analysis.Moves[i] = messages.MoveAnalysis{
+			Player:    review.Turn.Player,
+			X:         review.Turn.X,
+			Y:         review.Turn.Y,
+			Score:     finite(review.Score),
+			BestX:     review.Best[0],
+			BestY:     review.Best[1],
+			BestScore: finite(review.BestScore),
+			Swing:     finite(review.Swing),
+			Mistake:   string(review.Mistake),
+		}
 	}
 
-	return nextState
+	return analysis
 }
@@ -28,6 +28,7 @@ const (
 	attribOpponent    = "Opponent"
 	attribGame        = "Game"
 	attribConnections = "Connections"
+	attribAllowHints  = "AllowHints"
 
 	attribNickname = "Nickname"
 	attribInGame   = "InGame"
@@ -38,9 +39,23 @@ const (
 const indexByOpponent = "ByOpponent"
 
 type game struct {
-	Board      common.Board
+	common.Match
 	Difficulty int
-	Player     common.Disk
+
+	// Engine is the name of the AI engine of a solo game, or empty for the default engine.
+	Engine string `json:",omitempty"`
+
+	// Human is the player of the human in a solo game. It is zero in multiplayer games, and in solo
+	// games that were saved before the human could choose, where the human is player 1.
+	Human common.Disk `json:",omitempty"`
+}
+
+// human returns the player of the human in a solo game.
+func (g game) human() common.Disk {
+	if g.Human == 0 {
+		return common.Player1
+	}
+	return g.Human
 }
 
 func getGame(ctx context.Context, args Args, host string) (game, string, map[string]string, error) {
@@ -72,6 +87,21 @@ func getGame(ctx context.Context, args Args, host string) (game, string, map[str
 	return game, item.Opponent, item.Connections, err
 }
 
+func getAllowHints(ctx context.Context, args Args, host string) (map[string]bool, error) {
+	output, err := args.DB.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(args.TableName),
+		Key:       hostKey(host),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var item struct{ AllowHints map[string]bool }
+	err = dynamodbattribute.UnmarshalMap(output.Item, &item)
+
+	return item.AllowHints, err
+}
+
 func updateGame(ctx context.Context, args Args, host string, game game, connName, connID string) error {
 	gameBytes, err := json.Marshal(&game)
 	if err != nil {
@@ -85,7 +115,7 @@ func updateGame(ctx context.Context, args Args, host string, game game, connName
 	return err
 }
 
-func createGame(ctx context.Context, args Args, host string, game game, opponent, connName, connID string) error {
+func createGame(ctx context.Context, args Args, host string, game game, opponent, connName, connID string, allowHints bool) error {
 	gameBytes, err := json.Marshal(&game)
 	if err != nil {
 		return err
@@ -93,7 +123,8 @@ func createGame(ctx context.Context, args Args, host string, game game, opponent
 
 	update := expression.
 		Set(expression.Name(attribGame), expression.Value(gameBytes)).
-		Set(expression.Name(attribConnections), expression.Value(map[string]string{connName: connID}))
+		Set(expression.Name(attribConnections), expression.Value(map[string]string{connName: connID})).
+		Set(expression.Name(attribAllowHints), expression.Value(map[string]bool{connName: allowHints}))
 
 	if opponent != "" {
 		update = update.Set(expression.Name(attribOpponent), expression.Value(opponent))
@@ -105,10 +136,11 @@ func createGame(ctx context.Context, args Args, host string, game game, opponent
 	return err
 }
 
-func updateOpponentConnectionGetGameConnectionIDs(ctx context.Context, args Args, host, opponent, connName, connID string, expectedOpponents [2]string) (game, []string, error) {
+func updateOpponentConnectionGetGameConnectionIDs(ctx context.Context, args Args, host, opponent, connName, connID string, allowHints bool, expectedOpponents [2]string) (game, []string, error) {
 	update := expression.
 		Set(expression.Name(attribOpponent), expression.Value(opponent)).
-		Set(expression.Name(attribConnections+"."+connName), expression.Value(connID))
+		Set(expression.Name(attribConnections+"."+connName), expression.Value(connID)).
+		Set(expression.Name(attribAllowHints+"."+connName), expression.Value(allowHints))
 	condition := expression.In(expression.Name(attribOpponent), expression.Value(expectedOpponents[0]), expression.Value(expectedOpponents[1]))
 
 	output, err := updateItemWithCondition(ctx, args, host, update, condition, true)
@@ -5,13 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"time"
 
 	"github.com/armsnyder/othelgo/pkg/common"
 
 	"github.com/aws/aws-lambda-go/events"
 
+	"github.com/armsnyder/othelgo/pkg/ai"
 	"github.com/armsnyder/othelgo/pkg/messages"
 )
 
@@ -23,32 +22,16 @@ func handlePlaceDisk(ctx context.Context, req events.APIGatewayWebsocketProxyReq
 		return fmt.Errorf("failed to load game state: %w", err)
 	}
 
-	authorized := false
-	for k, v := range connections {
-		if k == message.Nickname && v == req.RequestContext.ConnectionID {
-			authorized = true
-			break
-		}
-	}
-
-	if !authorized {
+	if !authorized(connections, message.Nickname, req.RequestContext.ConnectionID) {
 		return errors.New("unauthorized")
 	}
 
-	var player common.Disk = 1
-	if message.Host != message.Nickname {
-		player = 2
+	if playerOf(game, opponent, message.Host, message.Nickname) != game.Player {
+		return reply(ctx, req.RequestContext, args, newUpdateBoard(game, -1, -1))
 	}
-	if player != game.Player {
-		p1Score, p2Score := common.KeepScore(game.Board)
-		return reply(ctx, req.RequestContext, args, messages.UpdateBoard{
-			Board:   game.Board,
-			Player:  game.Player,
-			X:       -1,
-			Y:       -1,
-			P1Score: p1Score,
-			P2Score: p2Score,
-		})
+
+	if !game.Board.InBounds(message.X, message.Y) {
+		return fmt.Errorf("move (%d, %d) is off the edge of a board of size %d", message.X, message.Y, game.Board.Size())
 	}
 
 	var connectionIDs []string
@@ -60,78 +43,71 @@ func handlePlaceDisk(ctx context.Context, req events.APIGatewayWebsocketProxyReq
 		return handlePlaceDiskSolo(ctx, req.RequestContext, args, message, game)
 	}
 
-	return handlePlaceDiskMultiplayer(ctx, req.RequestContext, args, message, game, opponent, connectionIDs)
+	return handlePlaceDiskMultiplayer(ctx, req.RequestContext, args, message, game, connectionIDs)
 }
 
-func handlePlaceDiskSolo(ctx context.Context, reqCtx events.APIGatewayWebsocketProxyRequestContext, args Args, message *messages.PlaceDisk, game game) error {
-	board, updated := common.ApplyMove(game.Board, message.X, message.Y, 1)
-	p1Score, p2Score := common.KeepScore(board)
-
-	if !updated {
-		return reply(ctx, reqCtx, args, messages.UpdateBoard{
-			Board:   board,
-			Player:  game.Player,
-			X:       -1,
-			Y:       -1,
-			P1Score: p1Score,
-			P2Score: p2Score,
-		})
+// authorized returns true if the connection belongs to the player with the nickname in a game
+// with the given connections.
+func authorized(connections map[string]string, nickname, connID string) bool {
+	for k, v := range connections {
+		if k == nickname && v == connID {
+			return true
+		}
 	}
+	return false
+}
 
-	game.Board = board
+// playerOf returns the player of the user with the nickname in a game. The host is player 1 of a
+// multiplayer game, and the opponent is player 2.
+func playerOf(game game, opponent, host, nickname string) common.Disk {
+	switch {
+	case opponent == "":
+		return game.human()
+	case host != nickname:
+		return 2
+	default:
+		return 1
+	}
+}
 
-	if common.HasMoves(board, game.Player%2+1) {
-		game.Player = game.Player%2 + 1
+func handlePlaceDiskSolo(ctx context.Context, reqCtx events.APIGatewayWebsocketProxyRequestContext, args Args, message *messages.PlaceDisk, game game) error {
+	if err := game.Play(message.X, message.Y); err != nil {
+		log.Printf("Rejected move: %v", err)
+		return reply(ctx, reqCtx, args, newUpdateBoard(game, -1, -1))
 	}
 
 	if err := updateGame(ctx, args, message.Host, game, message.Nickname, reqCtx.ConnectionID); err != nil {
 		return fmt.Errorf("failed to save updated game state: %w", err)
 	}
 
-	if err := reply(ctx, reqCtx, args, messages.UpdateBoard{
-		Board:   board,
-		Player:  game.Player,
-		X:       message.X,
-		Y:       message.Y,
-		P1Score: p1Score,
-		P2Score: p2Score,
-	}); err != nil {
+	if err := reply(ctx, reqCtx, args, newUpdateBoard(game, message.X, message.Y)); err != nil {
 		return err
 	}
 
-	for game.Player == 2 && common.HasMoves(game.Board, 2) {
-		log.Println("Taking AI turn")
-
-		turnStartedAt := time.Now()
-
-		var coordinates [2]int
-
-		game.Board, coordinates = doAIPlayerMove(game.Board, game.Difficulty)
+	return takeAITurns(ctx, reqCtx, args, message.Host, game)
+}
 
-		p1Score, p2Score = common.KeepScore(game.Board)
+// takeAITurns makes moves for the AI players in a solo game until it is the human player's turn
+// or the game is over, sending the board to the human player after each move. The AI plays every
+// player other than the human.
+func takeAITurns(ctx context.Context, reqCtx events.APIGatewayWebsocketProxyRequestContext, args Args, host string, game game) error {
+	for game.Player != game.human() && !game.IsOver() {
+		log.Println("Taking AI turn")
 
-		// Pad the turn time in case the AI was very quick, so the player doesn't stress or know
-		// they're losing. (Sleep is disabled during tests.)
-		if os.Getenv("AWS_EXECUTION_ENV") != "" {
-			time.Sleep(time.Second - time.Since(turnStartedAt))
+		coordinates, err := doAIPlayerMove(ctx, args, game)
+		if err != nil {
+			return fmt.Errorf("AI failed to move: %w", err)
 		}
 
-		if common.HasMoves(game.Board, 1) {
-			game.Player = 1
+		if err := game.Play(coordinates[0], coordinates[1]); err != nil {
+			return fmt.Errorf("AI made an illegal move: %w", err)
 		}
 
-		if err := updateGame(ctx, args, message.Host, game, message.Nickname, reqCtx.ConnectionID); err != nil {
+		if err := updateGame(ctx, args, host, game, host, reqCtx.ConnectionID); err != nil {
 			return fmt.Errorf("failed to save updated game state: %w", err)
 		}
 
-		if err := reply(ctx, reqCtx, args, messages.UpdateBoard{
-			Board:   game.Board,
-			Player:  game.Player,
-			X:       coordinates[0],
-			Y:       coordinates[1],
-			P1Score: p1Score,
-			P2Score: p2Score,
-		}); err != nil {
+		if err := reply(ctx, reqCtx, args, newUpdateBoard(game, coordinates[0], coordinates[1])); err != nil {
 			return err
 		}
 	}
@@ -139,41 +115,89 @@ func handlePlaceDiskSolo(ctx context.Context, reqCtx events.APIGatewayWebsocketP
 	return nil
 }
 
-func handlePlaceDiskMultiplayer(ctx context.Context, reqCtx events.APIGatewayWebsocketProxyRequestContext, args Args, message *messages.PlaceDisk, game game, opponent string, connectionIDs []string) error {
-	player := common.Player1
-	if message.Nickname == opponent {
-		player = common.Player2
+func handlePlaceDiskMultiplayer(ctx context.Context, reqCtx events.APIGatewayWebsocketProxyRequestContext, args Args, message *messages.PlaceDisk, game game, connectionIDs []string) error {
+	if err := game.Play(message.X, message.Y); err != nil {
+		log.Printf("Rejected move: %v", err)
+		return reply(ctx, reqCtx, args, newUpdateBoard(game, -1, -1))
 	}
 
-	board, updated := common.ApplyMove(game.Board, message.X, message.Y, player)
-	p1Score, p2Score := common.KeepScore(board)
-	if !updated {
-		return reply(ctx, reqCtx, args, messages.UpdateBoard{
-			Board:   board,
-			Player:  game.Player,
-			X:       -1,
-			Y:       -1,
-			P1Score: p1Score,
-			P2Score: p2Score,
-		})
+	if err := updateGame(ctx, args, message.Host, game, message.Nickname, reqCtx.ConnectionID); err != nil {
+		return fmt.Errorf("failed to save updated game state: %w", err)
 	}
 
-	game.Board = board
+	return broadcast(ctx, reqCtx, args, newUpdateBoard(game, message.X, message.Y), connectionIDs)
+}
 
-	if common.HasMoves(game.Board, player%2+1) {
-		game.Player = player%2 + 1
+func handleRequestHint(ctx context.Context, req events.APIGatewayWebsocketProxyRequest, args Args, message *messages.RequestHint) error {
+	game, opponent, connections, err := getGame(ctx, args, message.Host)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
 	}
 
-	if err := updateGame(ctx, args, message.Host, game, message.Nickname, reqCtx.ConnectionID); err != nil {
-		return fmt.Errorf("failed to save updated game state: %w", err)
+	if !authorized(connections, message.Nickname, req.RequestContext.ConnectionID) {
+		return errors.New("unauthorized")
 	}
 
-	return broadcast(ctx, reqCtx, args, messages.UpdateBoard{
-		Board:   board,
-		Player:  game.Player,
-		X:       message.X,
-		Y:       message.Y,
-		P1Score: p1Score,
-		P2Score: p2Score,
-	}, connectionIDs)
+	// Hints are always allowed against the AI, but a human opponent must agree to them.
+	if opponent != "" {
+		allowHints, err := getAllowHints(ctx, args, message.Host)
+		if err != nil {
+			return fmt.Errorf("failed to load hint settings: %w", err)
+		}
+
+		if !allowHints[message.Host] || !allowHints[opponent] {
+			return errors.New("hints are not allowed in this game")
+		}
+	}
+
+	if game.IsOver() || playerOf(game, opponent, message.Host, message.Nickname) != game.Player {
+		return errors.New("hints are only given on the player's own turn")
+	}
+
+	scores, err := ai.Minimax{}.Analyze(ctx, game.Board, game.Player, hintLimits(game))
+	if err != nil {
+		return fmt.Errorf("failed to analyze position: %w", err)
+	}
+
+	return reply(ctx, req.RequestContext, args, newAnalysis(scores))
+}
+
+func handleRequestGameAnalysis(ctx context.Context, req events.APIGatewayWebsocketProxyRequest, args Args, message *messages.RequestGameAnalysis) error {
+	game, _, connections, err := getGame(ctx, args, message.Host)
+	if err != nil {
+		return fmt.Errorf("failed to load game state: %w", err)
+	}
+
+	if !authorized(connections, message.Nickname, req.RequestContext.ConnectionID) {
+		return errors.New("unauthorized")
+	}
+
+	// Reviewing an unfinished game would be a way to get hints.
+	if !game.IsOver() {
+		return errors.New("the game is not over")
+	}
+
+	reviewCtx, cancel := context.WithTimeout(ctx, reviewBudget)
+	defer cancel()
+
+	reviews, err := ai.Review(reviewCtx, game.Match, reviewLimits(game))
+	if err != nil {
+		return fmt.Errorf("failed to review game: %w", err)
+	}
+
+	return reply(ctx, req.RequestContext, args, newGameAnalysis(reviews))
+}
+
+// newUpdateBoard returns an UpdateBoard message for the current state of the game. The coordinates
+// are those of the last move, or -1 if there was no move.
+func newUpdateBoard(game game, x, y int) messages.UpdateBoard {
+	return messages.UpdateBoard{
+		Board:       game.Board,
+		Player:      game.Player,
+		X:           x,
+		Y:           y,
+		Scores:      game.Scores(),
+		Variant:     game.Variant,
+		HumanPlayer: game.Human,
+	}
 }
@@ -2,14 +2,18 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
 	"strings"
+	"time"
 
 	"github.com/armsnyder/othelgo/pkg/common"
 
 	"github.com/aws/aws-lambda-go/events"
 
+	"github.com/armsnyder/othelgo/pkg/ai"
 	"github.com/armsnyder/othelgo/pkg/messages"
 )
 
@@ -21,6 +25,16 @@ const waiting = "#waiting"
 func handleHostGame(ctx context.Context, req events.APIGatewayWebsocketProxyRequest, args Args, message *messages.HostGame) error {
 	log.Printf("User %q is hosting a new game", message.Nickname)
 
+	game, err := newGame(message.GameSettings)
+	if err != nil {
+		return err
+	}
+
+	// A custom board decides the number of players too, so check the game rather than the message.
+	if game.Players > 2 {
+		return errors.New("games with more than two players can only be played solo")
+	}
+
 	prevNickname, prevInGame, err := updateInGame(ctx, args, req.RequestContext.ConnectionID, message.Nickname, message.Nickname)
 	if err != nil {
 		return err
@@ -36,25 +50,32 @@ func handleHostGame(ctx context.Context, req events.APIGatewayWebsocketProxyRequ
 		}
 	}
 
-	game := newGame()
-
-	if err := createGame(ctx, args, message.Nickname, game, waiting, message.Nickname, req.RequestContext.ConnectionID); err != nil {
+	if err := createGame(ctx, args, message.Nickname, game, waiting, message.Nickname, req.RequestContext.ConnectionID, message.AllowHints); err != nil {
 		return fmt.Errorf("failed to save new game state: %w", err)
 	}
 
-	return reply(ctx, req.RequestContext, args, messages.UpdateBoard{
-		Board:   game.Board,
-		Player:  game.Player,
-		X:       -1,
-		Y:       -1,
-		P1Score: 2,
-		P2Score: 2,
-	})
+	return reply(ctx, req.RequestContext, args, newUpdateBoard(game, -1, -1))
 }
 
 func handleStartSoloGame(ctx context.Context, req events.APIGatewayWebsocketProxyRequest, args Args, message *messages.StartSoloGame) error {
 	log.Printf("User %q is starting a new solo game", message.Nickname)
 
+	if _, ok := ai.Lookup(message.Engine); !ok {
+		return fmt.Errorf("unknown AI engine %q", message.Engine)
+	}
+
+	// A handicap helps the human, unless it says otherwise.
+	settings := message.GameSettings
+	human := humanPlayer(message.Color, settings.Players)
+	if settings.HandicapPlayer == 0 {
+		settings.HandicapPlayer = human
+	}
+
+	game, err := newGame(settings)
+	if err != nil {
+		return err
+	}
+
 	prevNickname, prevInGame, err := updateInGame(ctx, args, req.RequestContext.ConnectionID, message.Nickname, message.Nickname)
 	if err != nil {
 		return err
@@ -70,35 +91,101 @@ func handleStartSoloGame(ctx context.Context, req events.APIGatewayWebsocketProx
 		}
 	}
 
-	game := newGame()
 	game.Difficulty = message.Difficulty
+	game.Engine = message.Engine
+	game.Human = human
 
-	if err := createGame(ctx, args, message.Nickname, game, "", message.Nickname, req.RequestContext.ConnectionID); err != nil {
+	if err := createGame(ctx, args, message.Nickname, game, "", message.Nickname, req.RequestContext.ConnectionID, true); err != nil {
 		return fmt.Errorf("failed to save new game state: %w", err)
 	}
 
-	return reply(ctx, req.RequestContext, args, messages.UpdateBoard{
-		Board:   game.Board,
-		Player:  game.Player,
-		X:       -1,
-		Y:       -1,
-		P1Score: 2,
-		P2Score: 2,
-	})
+	if err := reply(ctx, req.RequestContext, args, newUpdateBoard(game, -1, -1)); err != nil {
+		return err
+	}
+
+	// The AI moves first if the human chose to play second, or if a custom starting position says
+	// so.
+	return takeAITurns(ctx, req.RequestContext, args, message.Nickname, game)
+}
+
+// humanPlayer returns the player that the human plays in a solo game of the given number of
+// players, or of two players if it is zero. A random color may be any of the players.
+func humanPlayer(color messages.Color, players int) common.Disk {
+	if players == 0 {
+		players = 2
+	}
+
+	switch color {
+	case messages.White:
+		return common.Player2
+	case messages.RandomColor:
+		return common.Disk(1 + rand.New(rand.NewSource(time.Now().UnixNano())).Intn(players))
+	default:
+		return common.Player1
+	}
 }
 
-func newGame() game {
-	var board common.Board
+// newGame returns a game with the given settings. It returns an error if the game cannot start
+// from the requested position.
+func newGame(settings messages.GameSettings) (game, error) {
+	size := settings.BoardSize
+	if size == 0 {
+		size = common.DefaultBoardSize
+	}
+
+	players := settings.Players
+	if players == 0 {
+		players = 2
+	}
+
+	start := common.NewMatchForPlayers(size, players)
+	board, player := start.Board, start.Player
+
+	if settings.Board != nil {
+		if settings.BoardSize != 0 && settings.BoardSize != settings.Board.Size() {
+			return game{}, fmt.Errorf("board has size %d but the requested size is %d", settings.Board.Size(), settings.BoardSize)
+		}
+
+		board = *settings.Board
+		if settings.FirstPlayer != 0 {
+			player = settings.FirstPlayer
+		}
+
+		// The handicap and obstacles need a board of a supported size to be placed on.
+		if !common.ValidBoardSize(board.Size()) {
+			return game{}, fmt.Errorf("unsupported board size %d", board.Size())
+		}
+	}
+
+	handicapPlayer := settings.HandicapPlayer
+	if handicapPlayer == 0 {
+		handicapPlayer = 1
+	}
+	if int(handicapPlayer) > players {
+		return game{}, fmt.Errorf("handicap player %d is not in a game of %d players", handicapPlayer, players)
+	}
 
-	board[3][3] = 1
-	board[3][4] = 2
-	board[4][3] = 2
-	board[4][4] = 1
+	// A custom board may already have something on the corners, which the handicap would replace.
+	for _, corner := range settings.Handicap.Squares(board.Size()) {
+		if board.At(corner[0], corner[1]) != 0 {
+			return game{}, fmt.Errorf("handicap corner %s is not empty", common.FormatSquare(board.Size(), corner[0], corner[1]))
+		}
+	}
 
-	return game{
-		Board:  board,
-		Player: 1,
+	board = settings.Handicap.Apply(board, handicapPlayer)
+
+	if settings.Obstacles > 0 {
+		board = common.SeedObstacles(board, settings.Obstacles, rand.New(rand.NewSource(time.Now().UnixNano())))
+	}
+
+	if err := common.ValidateStart(board, player); err != nil {
+		return game{}, fmt.Errorf("invalid starting position: %w", err)
 	}
+
+	game := game{Match: common.NewMatchFromPosition(board, player)}
+	game.SetVariant(settings.Variant)
+
+	return game, nil
 }
 
 func handleJoinGame(ctx context.Context, req events.APIGatewayWebsocketProxyRequest, args Args, message *messages.JoinGame) error {
@@ -119,19 +206,12 @@ func handleJoinGame(ctx context.Context, req events.APIGatewayWebsocketProxyRequ
 		}
 	}
 
-	game, connectionIDs, err := updateOpponentConnectionGetGameConnectionIDs(ctx, args, message.Host, message.Nickname, message.Nickname, req.RequestContext.ConnectionID, [2]string{waiting, message.Nickname})
+	game, connectionIDs, err := updateOpponentConnectionGetGameConnectionIDs(ctx, args, message.Host, message.Nickname, message.Nickname, req.RequestContext.ConnectionID, message.AllowHints, [2]string{waiting, message.Nickname})
 	if err != nil {
 		return err
 	}
 
-	if err := reply(ctx, req.RequestContext, args, messages.UpdateBoard{
-		Board:   game.Board,
-		Player:  game.Player,
-		X:       -1,
-		Y:       -1,
-		P1Score: 2,
-		P2Score: 2,
-	}); err != nil {
+	if err := reply(ctx, req.RequestContext, args, newUpdateBoard(game, -1, -1)); err != nil {
 		return err
 	}
 
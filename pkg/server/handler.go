@@ -20,6 +20,10 @@ type Args struct {
 	DB                                   *dynamodb.DynamoDB
 	TableName                            string
 	APIGatewayManagementAPIClientFactory APIGatewayManagementAPIClientFactory
+
+	// AIWorkers is the number of goroutines that the AI of solo games searches with. If it is
+	// zero, the AI searches with one.
+	AIWorkers int
 }
 
 // DefaultHandler is an AWS Lambda handler that uses default arguments, as it would in a real
@@ -29,6 +33,7 @@ func DefaultHandler(ctx context.Context, req events.APIGatewayWebsocketProxyRequ
 		DB:                                   defaultDB(),
 		TableName:                            "Othelgo",
 		APIGatewayManagementAPIClientFactory: defaultAPIGatewayManagementAPIClientFactory(),
+		AIWorkers:                            defaultAIWorkers(),
 	}
 
 	return Handle(ctx, req, defaultArgs)
@@ -95,6 +100,10 @@ func handleMessage(ctx context.Context, req events.APIGatewayWebsocketProxyReque
 		return handleListOpenGames(ctx, req, args, m)
 	case *messages.PlaceDisk:
 		return handlePlaceDisk(ctx, req, args, m)
+	case *messages.RequestHint:
+		return handleRequestHint(ctx, req, args, m)
+	case *messages.RequestGameAnalysis:
+		return handleRequestGameAnalysis(ctx, req, args, m)
 	case *messages.Hello:
 		return handleHello(ctx, req, args, m)
 	}
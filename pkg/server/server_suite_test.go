@@ -109,6 +109,38 @@ var _ = Describe("Server", func() {
 			})
 		})
 
+		When("flame asks for a hint", func() {
+			BeforeEach(Send(&flame, messages.RequestHint{Nickname: "flame", Host: "flame"}))
+
+			It("should score each of flame's four opening moves", func() {
+				var message messages.Analysis
+				Expect(flame).To(HaveReceived(&message))
+				Expect(message.Moves).To(HaveLen(4))
+			})
+
+			It("should recommend the best move", func() {
+				var message messages.Analysis
+				Expect(flame).To(HaveReceived(&message))
+				Expect(message.X).To(Equal(message.Moves[0].X))
+				Expect(message.Y).To(Equal(message.Moves[0].Y))
+				for _, move := range message.Moves {
+					Expect(move.Score).To(BeNumerically("<=", message.Moves[0].Score))
+				}
+			})
+		})
+
+		When("zinger asks for a hint in flame's game", func() {
+			BeforeEach(Send(&zinger, messages.RequestHint{Nickname: "flame", Host: "flame"}))
+
+			It("should reply with an error", func() {
+				Expect(zinger).To(HaveReceived(&messages.Error{}))
+			})
+
+			It("should not send any analysis to zinger", func() {
+				Expect(zinger).NotTo(HaveReceived(&messages.Analysis{}))
+			})
+		})
+
 		When("flame moves", func() {
 			BeforeEach(Send(&flame, messages.PlaceDisk{Nickname: "flame", Host: "flame", X: 2, Y: 4}))
 
@@ -130,8 +162,7 @@ var _ = Describe("Server", func() {
 			It("should include the board score in the UpdateBoard message", func() {
 				var message messages.UpdateBoard
 				Expect(flame).To(HaveReceived(&message))
-				Expect(message.P1Score).To(BeNumerically("==", 3))
-				Expect(message.P2Score).To(BeNumerically("==", 3))
+				Expect(message.Scores).To(Equal([]int{3, 3}))
 			})
 
 			It("should be flame's turn", testutil.ExpectTurn(&flame, 1))
@@ -155,6 +186,354 @@ var _ = Describe("Server", func() {
 		})
 	})
 
+	When("flame starts a solo game on a small board", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{BoardSize: 6}}))
+
+		It("should send a small new game board to flame", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Board).To(Equal(testutil.BuildSizedBoard(6, []testutil.Move{{2, 2}, {3, 3}}, []testutil.Move{{2, 3}, {3, 2}})))
+		})
+
+		When("flame moves off the edge of the small board", func() {
+			BeforeEach(Send(&flame, messages.PlaceDisk{Nickname: "flame", Host: "flame", X: 6, Y: 6}))
+
+			It("should reply with an error", func() {
+				Expect(flame).To(HaveReceived(&messages.Error{}))
+			})
+		})
+
+		When("flame moves", func() {
+			BeforeEach(Send(&flame, messages.PlaceDisk{Nickname: "flame", Host: "flame", X: 1, Y: 3}))
+
+			It("should update the board with both flame and the AI's moves", func() {
+				var message messages.UpdateBoard
+				Expect(flame).To(HaveReceived(&message))
+				Expect(message.Board.Size()).To(Equal(6))
+				Expect(message.Scores[0] + message.Scores[1]).To(Equal(6))
+			})
+		})
+	})
+
+	When("flame hosts an anti-othello game", func() {
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{Variant: common.AntiOthello}}))
+
+		When("zinger joins flame's game", func() {
+			BeforeEach(Send(&zinger, messages.JoinGame{Nickname: "zinger", Host: "flame"}))
+
+			It("should tell zinger the game is anti-othello", func() {
+				var message messages.UpdateBoard
+				Expect(zinger).To(HaveReceived(&message))
+				Expect(message.Variant).To(Equal(common.AntiOthello))
+			})
+		})
+	})
+
+	When("flame hosts a game with an unsupported variant", func() {
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{Variant: "sideways"}}))
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame starts a solo game with a two corner handicap", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Handicap: common.TwoCorners}}))
+
+		It("should give flame two corners", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Board).To(Equal(testutil.ParseBoard("x7/8/8/3xo3/3ox3/8/8/7x")))
+		})
+
+		It("should be flame's turn", testutil.ExpectTurn(&flame, 1))
+	})
+
+	When("flame starts a solo game from a custom position where the AI moves first", func() {
+		board := testutil.BuildBoard([]testutil.Move{{3, 3}, {4, 4}}, []testutil.Move{{3, 4}, {4, 3}})
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Board: &board, FirstPlayer: 2}}))
+
+		It("should make the AI's move", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Scores).To(Equal([]int{1, 4}))
+		})
+
+		It("should be flame's turn", testutil.ExpectTurn(&flame, 1))
+	})
+
+	When("flame starts a solo game as white", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", Color: messages.White}))
+
+		It("should make the AI's opening move", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Scores).To(Equal([]int{4, 1}))
+		})
+
+		It("should tell flame that they are player 2", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.HumanPlayer).To(Equal(common.Player2))
+		})
+
+		It("should be flame's turn", testutil.ExpectTurn(&flame, 2))
+
+		When("flame moves", func() {
+			BeforeEach(func() {
+				var message messages.UpdateBoard
+				Expect(flame).To(HaveReceived(&message))
+				move := common.LegalMoves(message.Board, common.Player2)[0]
+				flame.Send(messages.PlaceDisk{Nickname: "flame", Host: "flame", X: move.X, Y: move.Y})
+			})
+
+			It("should update the board with both flame and the AI's moves", func() {
+				var message messages.UpdateBoard
+				Expect(flame).To(HaveReceived(&message))
+				Expect(message.Scores[0] + message.Scores[1]).To(Equal(7))
+			})
+
+			It("should be flame's turn", testutil.ExpectTurn(&flame, 2))
+		})
+	})
+
+	When("flame starts a solo game as a random color", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", Color: messages.RandomColor}))
+
+		It("should be flame's turn", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.HumanPlayer).To(Or(Equal(common.Player1), Equal(common.Player2)))
+			Expect(message.Player).To(Equal(message.HumanPlayer))
+		})
+	})
+
+	When("flame starts a solo game as white with a two corner handicap", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", Color: messages.White, GameSettings: messages.GameSettings{Handicap: common.TwoCorners}}))
+
+		It("should give flame two corners", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Board.At(0, 0)).To(Equal(common.Player2))
+			Expect(message.Board.At(7, 7)).To(Equal(common.Player2))
+		})
+	})
+
+	When("flame starts a four-player solo game as a random color with a corner handicap", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", Color: messages.RandomColor, GameSettings: messages.GameSettings{Players: 4, Handicap: common.OneCorner}}))
+
+		It("should give flame the corner, whichever player they are", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Board.At(0, 0)).To(Equal(message.HumanPlayer))
+		})
+	})
+
+	When("flame starts a three-player solo game with a corner handicap for player 3", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Players: 3, Handicap: common.OneCorner, HandicapPlayer: 3}}))
+
+		It("should give player 3 the corner", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Board.At(0, 0)).To(Equal(common.Player3))
+		})
+	})
+
+	When("flame starts a three-player solo game with a corner handicap for player 4", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Players: 3, Handicap: common.OneCorner, HandicapPlayer: 4}}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame starts a solo game with a corner handicap on a custom board whose corner is taken", func() {
+		board := testutil.BuildBoard([]testutil.Move{{3, 3}, {4, 4}}, []testutil.Move{{3, 4}, {4, 3}, {0, 0}})
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Board: &board, Handicap: common.OneCorner}}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame starts a solo game with an unsupported color", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", Color: "purple"}))
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame starts a solo game with four players", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Players: 4}}))
+
+		It("should send a board with four players to flame", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+			Expect(message.Scores).To(Equal([]int{4, 4, 4, 4}))
+		})
+
+		When("flame moves", func() {
+			BeforeEach(Send(&flame, messages.PlaceDisk{Nickname: "flame", Host: "flame", X: 1, Y: 3}))
+
+			It("should make a move for each of the three AI players", func() {
+				var message messages.UpdateBoard
+				Expect(flame).To(HaveReceived(&message))
+				Expect(message.Scores).To(HaveLen(4))
+				Expect(message.Scores[0] + message.Scores[1] + message.Scores[2] + message.Scores[3]).To(Equal(20))
+			})
+
+			It("should be flame's turn", testutil.ExpectTurn(&flame, 1))
+		})
+	})
+
+	When("flame starts a solo game against an unknown AI engine", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", Engine: "nonexistent"}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame starts a solo game that ends after one move", func() {
+		board := testutil.BuildBoard([]testutil.Move{{0, 0}}, []testutil.Move{{1, 0}})
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Board: &board}}))
+
+		When("flame asks for an analysis of the game", func() {
+			BeforeEach(Send(&flame, messages.RequestGameAnalysis{Nickname: "flame", Host: "flame"}))
+
+			It("should reply with an error", func() {
+				Expect(flame).To(HaveReceived(&messages.Error{}))
+			})
+
+			It("should not send any analysis to flame", func() {
+				Expect(flame).NotTo(HaveReceived(&messages.GameAnalysis{}))
+			})
+		})
+
+		When("flame moves", func() {
+			BeforeEach(Send(&flame, messages.PlaceDisk{Nickname: "flame", Host: "flame", X: 2, Y: 0}))
+
+			When("flame asks for an analysis of the game", func() {
+				BeforeEach(Send(&flame, messages.RequestGameAnalysis{Nickname: "flame", Host: "flame"}))
+
+				It("should review flame's move", func() {
+					var message messages.GameAnalysis
+					Expect(flame).To(HaveReceived(&message))
+					Expect(message.Moves).To(HaveLen(1))
+					Expect(message.Moves[0].Player).To(Equal(common.Player1))
+					Expect(message.Moves[0].X).To(Equal(2))
+					Expect(message.Moves[0].Y).To(Equal(0))
+				})
+
+				It("should find no mistake in flame's only legal move", func() {
+					var message messages.GameAnalysis
+					Expect(flame).To(HaveReceived(&message))
+					Expect(message.Moves[0].BestX).To(Equal(2))
+					Expect(message.Moves[0].BestY).To(Equal(0))
+					Expect(message.Moves[0].Swing).To(BeZero())
+					Expect(message.Moves[0].Mistake).To(BeEmpty())
+				})
+			})
+
+			When("zinger asks for an analysis of flame's game", func() {
+				BeforeEach(Send(&zinger, messages.RequestGameAnalysis{Nickname: "flame", Host: "flame"}))
+
+				It("should reply with an error", func() {
+					Expect(zinger).To(HaveReceived(&messages.Error{}))
+				})
+			})
+		})
+	})
+
+	When("flame hosts a game with three players", func() {
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{Players: 3}}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+	})
+
+	When("flame hosts a game from a custom position with three players", func() {
+		board := common.StartingBoard(common.DefaultBoardSize, 3)
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{Board: &board}}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame starts a solo game with obstacles", func() {
+		BeforeEach(Send(&flame, messages.StartSoloGame{Nickname: "flame", GameSettings: messages.GameSettings{Obstacles: 4}}))
+
+		It("should block 4 squares", func() {
+			var message messages.UpdateBoard
+			Expect(flame).To(HaveReceived(&message))
+
+			blocked := 0
+			for x := 0; x < message.Board.Size(); x++ {
+				for y := 0; y < message.Board.Size(); y++ {
+					if message.Board.At(x, y) == common.Blocked {
+						blocked++
+					}
+				}
+			}
+			Expect(blocked).To(Equal(4))
+		})
+
+		It("should be flame's turn", testutil.ExpectTurn(&flame, 1))
+	})
+
+	When("flame hosts a game from a custom position with no legal moves", func() {
+		board := testutil.BuildBoard([]testutil.Move{{0, 0}}, []testutil.Move{{7, 7}})
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{Board: &board}}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame hosts a game with a handicap from an empty custom board", func() {
+		board := common.NewBoard(0)
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{Board: &board, Handicap: common.OneCorner}}))
+
+		It("should reply with an error", func() {
+			Expect(flame).To(HaveReceived(&messages.Error{}))
+		})
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
+	When("flame hosts a game with an unsupported board size", func() {
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", GameSettings: messages.GameSettings{BoardSize: 7}}))
+
+		It("should not send any board to flame", func() {
+			Expect(flame).NotTo(HaveReceived(&messages.UpdateBoard{}))
+		})
+	})
+
 	When("flame hosts a game", func() {
 		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame"}))
 
@@ -358,8 +737,7 @@ var _ = Describe("Server", func() {
 				It("should include the board score in the UpdateBoard message", func() {
 					var message messages.UpdateBoard
 					Expect(flame).To(HaveReceived(&message))
-					Expect(message.P1Score).To(BeNumerically("==", 4))
-					Expect(message.P2Score).To(BeNumerically("==", 1))
+					Expect(message.Scores).To(Equal([]int{4, 1}))
 				})
 
 				It("should show flame it is player 2's turn", testutil.ExpectTurn(&flame, 2))
@@ -380,8 +758,7 @@ var _ = Describe("Server", func() {
 				It("should include the board score in the UpdateBoard message", func() {
 					var message messages.UpdateBoard
 					Expect(zinger).To(HaveReceived(&message))
-					Expect(message.P1Score).To(BeNumerically("==", 4))
-					Expect(message.P2Score).To(BeNumerically("==", 1))
+					Expect(message.Scores).To(Equal([]int{4, 1}))
 				})
 
 				It("should show zinger it is player 2's turn", testutil.ExpectTurn(&zinger, 2))
@@ -397,6 +774,18 @@ var _ = Describe("Server", func() {
 				})
 			})
 
+			When("flame asks for a hint", func() {
+				BeforeEach(Send(&flame, messages.RequestHint{Nickname: "flame", Host: "flame"}))
+
+				It("should reply with an error", func() {
+					Expect(flame).To(HaveReceived(&messages.Error{}))
+				})
+
+				It("should not send any analysis to flame", func() {
+					Expect(flame).NotTo(HaveReceived(&messages.Analysis{}))
+				})
+			})
+
 			When("zinger impersonates flame to take flame's turn", func() {
 				BeforeEach(Send(&zinger, messages.PlaceDisk{Nickname: "flame", Host: "flame", X: 2, Y: 4}))
 
@@ -424,4 +813,45 @@ var _ = Describe("Server", func() {
 			})
 		})
 	})
+	When("flame hosts a game that allows hints", func() {
+		BeforeEach(Send(&flame, messages.HostGame{Nickname: "flame", AllowHints: true}))
+
+		When("zinger joins the game and allows hints", func() {
+			BeforeEach(Send(&zinger, messages.JoinGame{Nickname: "zinger", Host: "flame", AllowHints: true}))
+
+			When("flame asks for a hint", func() {
+				BeforeEach(Send(&flame, messages.RequestHint{Nickname: "flame", Host: "flame"}))
+
+				It("should score each of flame's four opening moves", func() {
+					var message messages.Analysis
+					Expect(flame).To(HaveReceived(&message))
+					Expect(message.Moves).To(HaveLen(4))
+				})
+			})
+
+			When("zinger asks for a hint on flame's turn", func() {
+				BeforeEach(Send(&zinger, messages.RequestHint{Nickname: "zinger", Host: "flame"}))
+
+				It("should reply with an error", func() {
+					Expect(zinger).To(HaveReceived(&messages.Error{}))
+				})
+			})
+		})
+
+		When("zinger joins the game and does not allow hints", func() {
+			BeforeEach(Send(&zinger, messages.JoinGame{Nickname: "zinger", Host: "flame"}))
+
+			When("flame asks for a hint", func() {
+				BeforeEach(Send(&flame, messages.RequestHint{Nickname: "flame", Host: "flame"}))
+
+				It("should reply with an error", func() {
+					Expect(flame).To(HaveReceived(&messages.Error{}))
+				})
+
+				It("should not send any analysis to flame", func() {
+					Expect(flame).NotTo(HaveReceived(&messages.Analysis{}))
+				})
+			})
+		})
+	})
 })
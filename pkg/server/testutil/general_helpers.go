@@ -16,19 +16,34 @@ func DumpTableOnFailure() {
 
 type Move [2]int
 
-func BuildBoard(p1, p2 []Move) (board common.Board) {
+func BuildBoard(p1, p2 []Move) common.Board {
+	return BuildSizedBoard(common.DefaultBoardSize, p1, p2)
+}
+
+func BuildSizedBoard(size int, p1, p2 []Move) common.Board {
+	board := common.NewBoard(size)
+
 	for i, moves := range [][]Move{p1, p2} {
 		player := common.Disk(i + 1)
 
 		for _, move := range moves {
 			x, y := move[0], move[1]
-			board[x][y] = player
+			board.Set(x, y, player)
 		}
 	}
 
 	return board
 }
 
+// ParseBoard is common.ParseBoard for board literals in tests. It panics if the text is invalid.
+func ParseBoard(text string) common.Board {
+	board, _, err := common.ParseBoard(text)
+	if err != nil {
+		panic(err)
+	}
+	return board
+}
+
 // Send is a convenience wrapper around Client.Send which can be used directly as an argument to
 // ginkgo.BeforeEach.
 func Send(client **Client, messageToSend interface{}) func() {
@@ -0,0 +1,255 @@
+// Package wthor reads and writes game databases in the WTHOR (.wtb) format, which is the binary
+// format used by the French Othello Federation to publish tournament games.
+//
+// A WTHOR file is a 16-byte header followed by a 68-byte record for each game. Only files of 8x8
+// games are supported.
+package wthor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+)
+
+// maxMoves is the number of move slots in a game record. Unused slots are zero.
+const maxMoves = 60
+
+// Database is the contents of a WTHOR file.
+type Database struct {
+	// Created is the date that the file was created.
+	Created time.Time
+
+	// Year is the year that the games were played.
+	Year int
+
+	// Depth is the search depth from which the theoretical scores of the games are exact.
+	Depth int
+
+	Games []Game
+}
+
+// Game is a single game in a Database. Tournaments and players are stored as numbers, which refer
+// to the separate WTHOR tournament (.trn) and player (.jou) files.
+type Game struct {
+	Tournament int
+	Black      int
+	White      int
+
+	// BlackScore is the number of disks that black had at the end of the game.
+	BlackScore int
+
+	// TheoreticalScore is the number of disks that black would have had with perfect play from
+	// Database.Depth empty squares before the end of the game.
+	TheoreticalScore int
+
+	// Moves are the (x, y) coordinates of the moves of the game in order, not including passes.
+	Moves [][2]int
+}
+
+// fileHeader is the binary layout of the header of a WTHOR file. All integers are little-endian.
+type fileHeader struct {
+	CreatedCentury uint8
+	CreatedYear    uint8
+	CreatedMonth   uint8
+	CreatedDay     uint8
+	GameCount      uint32
+	RecordCount    uint16
+	Year           uint16
+	BoardSize      uint8
+	GameType       uint8
+	Depth          uint8
+	Reserved       uint8
+}
+
+// gameRecord is the binary layout of a game in a WTHOR file.
+type gameRecord struct {
+	Tournament       uint16
+	Black            uint16
+	White            uint16
+	BlackScore       uint8
+	TheoreticalScore uint8
+	Moves            [maxMoves]uint8
+}
+
+// Read decodes a WTHOR file. The moves of each game are decoded but not checked for legality. Use
+// Game.Match to replay them.
+func Read(r io.Reader) (Database, error) {
+	var header fileHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return Database{}, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	// Older files use zero to mean the standard board size.
+	if header.BoardSize != 0 && header.BoardSize != common.DefaultBoardSize {
+		return Database{}, fmt.Errorf("unsupported board size %d", header.BoardSize)
+	}
+
+	db := Database{
+		Created: time.Date(
+			int(header.CreatedCentury)*100+int(header.CreatedYear),
+			time.Month(header.CreatedMonth),
+			int(header.CreatedDay),
+			0, 0, 0, 0, time.UTC),
+		Year:  int(header.Year),
+		Depth: int(header.Depth),
+	}
+
+	// The games are appended as they are read, rather than allocated up front, since a damaged
+	// file can claim to hold billions of them.
+	for i := uint32(0); i < header.GameCount; i++ {
+		var record gameRecord
+		if err := binary.Read(r, binary.LittleEndian, &record); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return Database{}, fmt.Errorf("failed to read game %d: %w", i+1, err)
+		}
+
+		game, err := decodeGame(record)
+		if err != nil {
+			return Database{}, fmt.Errorf("game %d: %w", i+1, err)
+		}
+
+		db.Games = append(db.Games, game)
+	}
+
+	return db, nil
+}
+
+// Write encodes a Database as a WTHOR file.
+func Write(w io.Writer, db Database) error {
+	header := fileHeader{
+		CreatedCentury: uint8(db.Created.Year() / 100),
+		CreatedYear:    uint8(db.Created.Year() % 100),
+		CreatedMonth:   uint8(db.Created.Month()),
+		CreatedDay:     uint8(db.Created.Day()),
+		GameCount:      uint32(len(db.Games)),
+		Year:           uint16(db.Year),
+		BoardSize:      common.DefaultBoardSize,
+		Depth:          uint8(db.Depth),
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	for i, game := range db.Games {
+		record, err := encodeGame(game)
+		if err != nil {
+			return fmt.Errorf("game %d: %w", i+1, err)
+		}
+
+		if err := binary.Write(w, binary.LittleEndian, record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewGame returns a Game with the moves and score of a Match. The Match should be played on a
+// board of the default size from the standard opening position.
+func NewGame(match common.Match) Game {
+	var game Game
+
+	for _, turn := range match.History {
+		if !turn.Pass {
+			game.Moves = append(game.Moves, [2]int{turn.X, turn.Y})
+		}
+	}
+
+	if match.Result != nil {
+		game.BlackScore = blackScore(*match.Result)
+		game.TheoreticalScore = game.BlackScore
+	}
+
+	return game
+}
+
+// Match replays the moves of the Game from the standard opening position. If a move is illegal,
+// the returned error names it, and the Match is left as it was after the previous move.
+func (g Game) Match() (common.Match, error) {
+	match := common.NewMatch(common.DefaultBoardSize)
+
+	for i, move := range g.Moves {
+		if err := match.Play(move[0], move[1]); err != nil {
+			name := common.FormatSquare(common.DefaultBoardSize, move[0], move[1])
+			return match, fmt.Errorf("move %d (%s): %w", i+1, name, err)
+		}
+	}
+
+	return match, nil
+}
+
+// blackScore returns the score of black (player 1) at the end of a game. By WTHOR convention,
+// empty squares at the end of the game are counted for the winner.
+func blackScore(result common.Result) int {
+	black, white := result.Scores[0], result.Scores[1]
+	empty := common.DefaultBoardSize*common.DefaultBoardSize - black - white
+
+	switch result.Winner {
+	case common.Player1:
+		return black + empty
+	case common.Player2:
+		return black
+	default:
+		return black + empty/2
+	}
+}
+
+// A move is encoded as 10*row + column, where the row and column of a square are numbered from 1
+// as in its name. For example, "f5" is encoded as 56.
+
+func decodeGame(record gameRecord) (Game, error) {
+	game := Game{
+		Tournament:       int(record.Tournament),
+		Black:            int(record.Black),
+		White:            int(record.White),
+		BlackScore:       int(record.BlackScore),
+		TheoreticalScore: int(record.TheoreticalScore),
+	}
+
+	for _, b := range record.Moves {
+		if b == 0 {
+			break
+		}
+
+		row, column := int(b/10), int(b%10)
+		if row < 1 || row > common.DefaultBoardSize || column < 1 || column > common.DefaultBoardSize {
+			return Game{}, fmt.Errorf("invalid move %d", b)
+		}
+
+		game.Moves = append(game.Moves, [2]int{column - 1, common.DefaultBoardSize - row})
+	}
+
+	return game, nil
+}
+
+func encodeGame(game Game) (gameRecord, error) {
+	record := gameRecord{
+		Tournament:       uint16(game.Tournament),
+		Black:            uint16(game.Black),
+		White:            uint16(game.White),
+		BlackScore:       uint8(game.BlackScore),
+		TheoreticalScore: uint8(game.TheoreticalScore),
+	}
+
+	if len(game.Moves) > maxMoves {
+		return gameRecord{}, fmt.Errorf("too many moves (%d)", len(game.Moves))
+	}
+
+	for i, move := range game.Moves {
+		x, y := move[0], move[1]
+		if x < 0 || x >= common.DefaultBoardSize || y < 0 || y >= common.DefaultBoardSize {
+			return gameRecord{}, fmt.Errorf("move %d (%d, %d) is out of bounds", i+1, x, y)
+		}
+
+		record.Moves[i] = uint8(10*(common.DefaultBoardSize-y) + x + 1)
+	}
+
+	return record, nil
+}
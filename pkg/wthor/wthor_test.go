@@ -0,0 +1,166 @@
+package wthor_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/armsnyder/othelgo/pkg/common"
+	. "github.com/armsnyder/othelgo/pkg/wthor"
+)
+
+// testFile is a WTHOR file containing one game, which was played in tournament 3 between players
+// 10 and 11, and which opens f5 d6 c3 d3 c4 before black resigns.
+var testFile = append([]byte{
+	20, 21, 3, 14, // Created 2021-03-14
+	1, 0, 0, 0, // 1 game
+	0, 0, // 0 records
+	0xe5, 0x07, // Played in 2021
+	8, 0, 22, 0, // 8x8 board, normal games, depth 22
+	3, 0, 10, 0, 11, 0, // Tournament and players
+	0, 1, // Scores
+	56, 64, 33, 34, 43,
+}, make([]byte, 55)...)
+
+var testDatabase = Database{
+	Created: time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC),
+	Year:    2021,
+	Depth:   22,
+	Games: []Game{{
+		Tournament:       3,
+		Black:            10,
+		White:            11,
+		BlackScore:       0,
+		TheoreticalScore: 1,
+		Moves:            [][2]int{{5, 3}, {3, 2}, {2, 5}, {3, 5}, {2, 4}},
+	}},
+}
+
+func TestRead(t *testing.T) {
+	got, err := Read(bytes.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, testDatabase) {
+		t.Errorf("Read() got %+v, want %+v", got, testDatabase)
+	}
+
+	match, err := got.Games[0].Match()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transcript := match.Transcript(); transcript != "f5d6c3d3c4" {
+		t.Errorf("Match() got transcript %q, want %q", transcript, "f5d6c3d3c4")
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Run("truncated", func(t *testing.T) {
+		_, err := Read(bytes.NewReader(testFile[:40]))
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Read() got error %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+
+	t.Run("game count", func(t *testing.T) {
+		file := append([]byte(nil), testFile...)
+		file[4], file[5], file[6], file[7] = 0xff, 0xff, 0xff, 0xff
+		_, err := Read(bytes.NewReader(file))
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Read() got error %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+
+	t.Run("board size", func(t *testing.T) {
+		file := append([]byte(nil), testFile...)
+		file[12] = 10
+		if _, err := Read(bytes.NewReader(file)); err == nil {
+			t.Error("Read() got no error")
+		}
+	})
+
+	t.Run("move", func(t *testing.T) {
+		file := append([]byte(nil), testFile...)
+		file[24] = 59
+		if _, err := Read(bytes.NewReader(file)); err == nil {
+			t.Error("Read() got no error")
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testDatabase); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), testFile) {
+		t.Errorf("Write() got %v, want %v", buf.Bytes(), testFile)
+	}
+}
+
+func TestGameMatchIllegalMove(t *testing.T) {
+	game := Game{Moves: [][2]int{{5, 3}, {0, 0}}}
+
+	_, err := game.Match()
+	if !errors.Is(err, common.ErrIllegalMove) {
+		t.Errorf("Match() got error %v, want %v", err, common.ErrIllegalMove)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	db := Database{Created: time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), Year: 2021}
+
+	var matches []common.Match
+
+	for i := 0; i < 50; i++ {
+		match := common.NewMatch(common.DefaultBoardSize)
+
+		for !match.IsOver() {
+			moves := match.LegalMoves()
+			move := moves[rng.Intn(len(moves))]
+			if err := match.Play(move.X, move.Y); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		matches = append(matches, match)
+		db.Games = append(db.Games, NewGame(match))
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, db); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, db) {
+		t.Fatalf("Read() got %+v, want %+v", got, db)
+	}
+
+	for i, game := range got.Games {
+		match, err := game.Match()
+		if err != nil {
+			t.Fatalf("game %d: %v", i+1, err)
+		}
+
+		if match.Board != matches[i].Board {
+			t.Errorf("game %d: Match() got board %v, want %v", i+1, match.Board, matches[i].Board)
+		}
+
+		if p1, _ := common.KeepScore(match.Board); game.BlackScore < p1 {
+			t.Errorf("game %d: got black score %d, want at least %d", i+1, game.BlackScore, p1)
+		}
+	}
+}
@@ -7,7 +7,9 @@ export type OutboundMessage =
   | JoinGame
   | LeaveGame
   | ListOpenGames
-  | PlaceDisk;
+  | PlaceDisk
+  | RequestHint
+  | RequestGameAnalysis;
 
 export type InboundMessage =
   | Joined
@@ -15,7 +17,9 @@ export type InboundMessage =
   | OpenGames
   | UpdateBoard
   | Error
-  | Decorate;
+  | Decorate
+  | Analysis
+  | GameAnalysis;
 
 export interface Hello {
   action: "hello";
@@ -25,18 +29,21 @@ export interface Hello {
 export interface HostGame {
   action: "hostGame";
   nickname: string;
+  allowHints?: boolean;
 }
 
 export interface StartSoloGame {
   action: "startSoloGame";
   nickname: string;
   difficulty: number;
+  color?: "black" | "white" | "random";
 }
 
 export interface JoinGame {
   action: "joinGame";
   nickname: string;
   host: string;
+  allowHints?: boolean;
 }
 
 export interface Joined {
@@ -78,8 +85,8 @@ export interface UpdateBoard {
   player: Player;
   x: number;
   y: number;
-  p1score: number;
-  p2score: number;
+  scores: number[];
+  humanPlayer?: Player;
 }
 
 export interface Error {
@@ -91,3 +98,45 @@ export interface Decorate {
   action: "decorate";
   decoration: string;
 }
+
+export interface RequestHint {
+  action: "requestHint";
+  nickname: string;
+  host: string;
+}
+
+export interface Analysis {
+  action: "analysis";
+  moves: MoveScore[];
+  x: number;
+  y: number;
+}
+
+export interface MoveScore {
+  x: number;
+  y: number;
+  score: number;
+}
+
+export interface RequestGameAnalysis {
+  action: "requestGameAnalysis";
+  nickname: string;
+  host: string;
+}
+
+export interface GameAnalysis {
+  action: "gameAnalysis";
+  moves: MoveAnalysis[];
+}
+
+export interface MoveAnalysis {
+  player: Player;
+  x: number;
+  y: number;
+  score: number;
+  bestX: number;
+  bestY: number;
+  bestScore: number;
+  swing: number;
+  mistake?: "inaccuracy" | "blunder";
+}